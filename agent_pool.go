@@ -0,0 +1,176 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+)
+
+// AgentPoolService manages the pools of on-premises agents, such as the AD
+// and LDAP agents, and their automatic updates.
+type AgentPoolService service
+
+// AgentPool is a pool of agents of the same type.
+type AgentPool struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"` // AD, LDAP, IWA, RUM, ...
+	OperationalStatus string   `json:"operationalStatus"`
+	DisruptedAgents   int      `json:"disruptedAgents"`
+	InactiveAgents    int      `json:"inactiveAgents"`
+	Agents            []*Agent `json:"agents"`
+}
+
+// Agent is an on-premises agent of an AgentPool.
+type Agent struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	Type                string `json:"type"`
+	PoolID              string `json:"poolId"`
+	Version             string `json:"version"`
+	OperationalStatus   string `json:"operationalStatus"`
+	UpdateStatus        string `json:"updateStatus"`
+	UpdateMessage       string `json:"updateMessage"`
+	IsHidden            bool   `json:"isHidden"`
+	IsLatestGAedVersion bool   `json:"isLatestGAedVersion"`
+	LastConnection      int64  `json:"lastConnection"` // Unix time in milliseconds
+}
+
+// AgentPoolUpdateSettings are the automatic update settings of an AgentPool.
+type AgentPoolUpdateSettings struct {
+	PoolID                  string `json:"poolId,omitempty"`
+	PoolName                string `json:"poolName,omitempty"`
+	AgentType               string `json:"agentType,omitempty"`
+	ReleaseChannel          string `json:"releaseChannel,omitempty"` // GA, BETA, EA or TEST
+	ContinueOnError         bool   `json:"continueOnError"`
+	LatestVersion           string `json:"latestVersion,omitempty"`
+	MinimalSupportedVersion string `json:"minimalSupportedVersion,omitempty"`
+}
+
+// AgentPoolUpdate is a scheduled or manual update of the agents of an AgentPool.
+type AgentPoolUpdate struct {
+	ID          string                   `json:"id,omitempty"`
+	Name        string                   `json:"name,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	AgentType   string                   `json:"agentType,omitempty"`
+	Status      string                   `json:"status,omitempty"`
+	Enabled     bool                     `json:"enabled"`
+	NotifyAdmin bool                     `json:"notifyAdmin"`
+	Schedule    *AgentPoolUpdateSchedule `json:"schedule,omitempty"`
+	Agents      []*Agent                 `json:"agents,omitempty"`
+}
+
+// AgentPoolUpdateSchedule is when an AgentPoolUpdate runs.
+type AgentPoolUpdateSchedule struct {
+	Cron     string `json:"cron"`
+	Delay    int    `json:"delay"`
+	Duration int    `json:"duration"` // in minutes
+	Timezone string `json:"timezone"`
+}
+
+// AgentPoolListOptions allows to filter the pools returned by List.
+type AgentPoolListOptions struct {
+	PoolType         string `url:"poolType,omitempty"`
+	LimitPerPoolType int    `url:"limitPerPoolType,omitempty"`
+	After            string `url:"after,omitempty"`
+}
+
+func agentPoolURL(poolID string) string {
+	return fmt.Sprintf("/api/v1/agentPools/%v", poolID)
+}
+
+// List returns the agent pools matching opt.
+func (s *AgentPoolService) List(ctx context.Context, opt *AgentPoolListOptions) ([]*AgentPool, *Response, error) {
+	u, err := addOptions("/api/v1/agentPools", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pools []*AgentPool
+	resp, err := s.client.call(ctx, "GET", u, nil, &pools)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return pools, resp, nil
+}
+
+// Get returns the agent pool poolID. Okta has no endpoint for a single pool,
+// so the pools are listed, page after page; a missing pool fails with the
+// *Response of the last page.
+func (s *AgentPoolService) Get(ctx context.Context, poolID string) (*AgentPool, *Response, error) {
+	opt := &AgentPoolListOptions{}
+	for {
+		pools, resp, err := s.List(ctx, opt)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, p := range pools {
+			if p.ID == poolID {
+				return p, resp, nil
+			}
+		}
+
+		if opt.After = cursorOf(resp); opt.After == "" || len(pools) == 0 {
+			return nil, resp, fmt.Errorf("okta: agent pool %v not found", poolID)
+		}
+	}
+}
+
+// GetUpdateSettings returns the automatic update settings of an agent pool.
+func (s *AgentPoolService) GetUpdateSettings(ctx context.Context, poolID string) (*AgentPoolUpdateSettings, *Response, error) {
+	var settings AgentPoolUpdateSettings
+	resp, err := s.client.call(ctx, "GET", agentPoolURL(poolID)+"/updates/settings", nil, &settings)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &settings, resp, nil
+}
+
+// UpdateUpdateSettings updates the automatic update settings of an agent pool.
+func (s *AgentPoolService) UpdateUpdateSettings(ctx context.Context, poolID string, settings *AgentPoolUpdateSettings) (*AgentPoolUpdateSettings, *Response, error) {
+	var updated AgentPoolUpdateSettings
+	resp, err := s.client.call(ctx, "POST", agentPoolURL(poolID)+"/updates/settings", settings, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// ListUpdates returns the updates of an agent pool.
+func (s *AgentPoolService) ListUpdates(ctx context.Context, poolID string) ([]*AgentPoolUpdate, *Response, error) {
+	var updates []*AgentPoolUpdate
+	resp, err := s.client.call(ctx, "GET", agentPoolURL(poolID)+"/updates", nil, &updates)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updates, resp, nil
+}
+
+// CreateUpdate creates an update of the agents of an agent pool.
+func (s *AgentPoolService) CreateUpdate(ctx context.Context, poolID string, update *AgentPoolUpdate) (*AgentPoolUpdate, *Response, error) {
+	var created AgentPoolUpdate
+	resp, err := s.client.call(ctx, "POST", agentPoolURL(poolID)+"/updates", update, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// UpdateUpdate updates an update of the agents of an agent pool,
+// such as its schedule.
+func (s *AgentPoolService) UpdateUpdate(ctx context.Context, poolID, updateID string, update *AgentPoolUpdate) (*AgentPoolUpdate, *Response, error) {
+	u := fmt.Sprintf("%v/updates/%v", agentPoolURL(poolID), updateID)
+
+	var updated AgentPoolUpdate
+	resp, err := s.client.call(ctx, "POST", u, update, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
@@ -0,0 +1,646 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+)
+
+// ErrNoSignOnPolicy is returned by GetSignOnPolicy when an application has no
+// access policy link, as in orgs without Identity Engine.
+var ErrNoSignOnPolicy = errors.New("okta: application has no sign-on policy")
+
+// ApplicationService manages the applications of the organisation.
+type ApplicationService service
+
+// Application statuses.
+const (
+	AppStatusActive   = "ACTIVE"
+	AppStatusInactive = "INACTIVE"
+)
+
+// Application is an application integrated with Okta.
+// The keys of Settings and Credentials depend on SignOnMode.
+type Application struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	Label       string                 `json:"label"`
+	Status      string                 `json:"status,omitempty"`
+	SignOnMode  string                 `json:"signOnMode"`
+	Features    []string               `json:"features,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Credentials map[string]interface{} `json:"credentials,omitempty"`
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes an application, mapping null or empty timestamps to the
+// zero time.
+func (a *Application) UnmarshalJSON(data []byte) error {
+	type alias Application
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.Created = time.Time(aux.Created)
+	a.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+func appURL(id string) string {
+	return fmt.Sprintf("/api/v1/apps/%v", id)
+}
+
+// Get returns an application.
+func (s *ApplicationService) Get(ctx context.Context, appID string) (*Application, *Response, error) {
+	var app Application
+	resp, err := s.client.call(ctx, "GET", appURL(appID), nil, &app)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &app, resp, nil
+}
+
+// GetSignOnPolicy returns the access policy governing the sign-on to an
+// application, as referenced by its accessPolicy link.
+func (s *ApplicationService) GetSignOnPolicy(ctx context.Context, appID string) (*Policy, *Response, error) {
+	app, resp, err := s.Get(ctx, appID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	link, ok := app.Links["accessPolicy"]
+	if !ok || link.Href == "" {
+		return nil, resp, ErrNoSignOnPolicy
+	}
+
+	return s.client.Policy.Get(ctx, path.Base(link.Href))
+}
+
+// AssignSignOnPolicy makes the access policy policyID govern the sign-on to
+// an application, replacing its current one.
+func (s *ApplicationService) AssignSignOnPolicy(ctx context.Context, appID, policyID string) (*Response, error) {
+	u := fmt.Sprintf("%v/policies/%v", appURL(appID), policyID)
+	return s.client.call(ctx, "PUT", u, nil, nil)
+}
+
+// Deactivate deactivates an application.
+func (s *ApplicationService) Deactivate(ctx context.Context, appID string) (*Response, error) {
+	return s.client.lifecycle(ctx, appURL(appID), "deactivate", nil, nil)
+}
+
+// Delete deletes an application. Okta only deletes inactive applications,
+// see DeactivateAndDelete.
+func (s *ApplicationService) Delete(ctx context.Context, appID string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", appURL(appID), nil, nil)
+}
+
+// DeactivateAndDelete deactivates an application, if it is active, and then deletes it.
+func (s *ApplicationService) DeactivateAndDelete(ctx context.Context, appID string) (*Response, error) {
+	app, resp, err := s.Get(ctx, appID)
+	if err != nil {
+		return resp, err
+	}
+
+	if app.Status == AppStatusActive {
+		if resp, err := s.Deactivate(ctx, appID); err != nil {
+			return resp, fmt.Errorf("deactivate application %v: %w", appID, err)
+		}
+	}
+
+	resp, err = s.Delete(ctx, appID)
+	if err != nil {
+		return resp, fmt.Errorf("delete application %v: %w", appID, err)
+	}
+
+	return resp, nil
+}
+
+// ClientSecret is a client secret of an OAuth application.
+// ClientSecret is only set when the secret is generated.
+type ClientSecret struct {
+	ID           string          `json:"id"`
+	Status       string          `json:"status"`
+	ClientSecret string          `json:"client_secret"`
+	SecretHash   string          `json:"secret_hash"`
+	Created      time.Time       `json:"created"`
+	LastUpdated  time.Time       `json:"lastUpdated"`
+	Links        map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a client secret, mapping null or empty timestamps to
+// the zero time.
+func (c *ClientSecret) UnmarshalJSON(data []byte) error {
+	type alias ClientSecret
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(c)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	c.Created = time.Time(aux.Created)
+	c.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// ListClientSecrets returns the client secrets of an OAuth application.
+func (s *ApplicationService) ListClientSecrets(ctx context.Context, appID string) ([]*ClientSecret, *Response, error) {
+	var secrets []*ClientSecret
+	resp, err := s.client.call(ctx, "GET", appURL(appID)+"/credentials/secrets", nil, &secrets)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return secrets, resp, nil
+}
+
+// GenerateClientSecret generates a new active client secret for an OAuth
+// application. The previous secrets stay active until deactivated, so that
+// clients can be moved to the new secret without downtime.
+func (s *ApplicationService) GenerateClientSecret(ctx context.Context, appID string) (*ClientSecret, *Response, error) {
+	var secret ClientSecret
+	resp, err := s.client.call(ctx, "POST", appURL(appID)+"/credentials/secrets", struct{}{}, &secret)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &secret, resp, nil
+}
+
+// ActivateClientSecret activates a client secret of an OAuth application.
+func (s *ApplicationService) ActivateClientSecret(ctx context.Context, appID, secretID string) (*ClientSecret, *Response, error) {
+	return s.clientSecretLifecycle(ctx, appID, secretID, "activate")
+}
+
+// DeactivateClientSecret deactivates a client secret of an OAuth application.
+// An application always keeps at least one active secret.
+func (s *ApplicationService) DeactivateClientSecret(ctx context.Context, appID, secretID string) (*ClientSecret, *Response, error) {
+	return s.clientSecretLifecycle(ctx, appID, secretID, "deactivate")
+}
+
+func (s *ApplicationService) clientSecretLifecycle(ctx context.Context, appID, secretID, action string) (*ClientSecret, *Response, error) {
+	u := fmt.Sprintf("%v/credentials/secrets/%v", appURL(appID), secretID)
+
+	var secret ClientSecret
+	resp, err := s.client.lifecycle(ctx, u, action, nil, &secret)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &secret, resp, nil
+}
+
+// DeleteClientSecret deletes a client secret of an OAuth application.
+// Okta only deletes inactive secrets, see DeactivateClientSecret.
+func (s *ApplicationService) DeleteClientSecret(ctx context.Context, appID, secretID string) (*Response, error) {
+	u := fmt.Sprintf("%v/credentials/secrets/%v", appURL(appID), secretID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// ListKeys returns the signing keys of an application.
+func (s *ApplicationService) ListKeys(ctx context.Context, appID string) ([]*JSONWebKey, *Response, error) {
+	var keys []*JSONWebKey
+	resp, err := s.client.call(ctx, "GET", appURL(appID)+"/credentials/keys", nil, &keys)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return keys, resp, nil
+}
+
+// GetKey returns a signing key of an application.
+func (s *ApplicationService) GetKey(ctx context.Context, appID, keyID string) (*JSONWebKey, *Response, error) {
+	u := fmt.Sprintf("%v/credentials/keys/%v", appURL(appID), keyID)
+
+	var key JSONWebKey
+	resp, err := s.client.call(ctx, "GET", u, nil, &key)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &key, resp, nil
+}
+
+// GenerateKey generates a signing key valid for validityYears, from 2 to 10.
+// The application keeps signing with its current key until its credentials
+// are updated to use the new one, which allows to publish it ahead of time.
+func (s *ApplicationService) GenerateKey(ctx context.Context, appID string, validityYears int) (*JSONWebKey, *Response, error) {
+	u, err := addOptions(appURL(appID)+"/credentials/keys/generate", &struct {
+		ValidityYears int `url:"validityYears"`
+	}{validityYears})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var key JSONWebKey
+	resp, err := s.client.call(ctx, "POST", u, nil, &key)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &key, resp, nil
+}
+
+// CloneKey copies a signing key of an application to the target application,
+// so that both can share it.
+func (s *ApplicationService) CloneKey(ctx context.Context, appID, keyID, targetAppID string) (*JSONWebKey, *Response, error) {
+	u, err := addOptions(fmt.Sprintf("%v/credentials/keys/%v/clone", appURL(appID), keyID), &struct {
+		TargetAid string `url:"targetAid"`
+	}{targetAppID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var key JSONWebKey
+	resp, err := s.client.call(ctx, "POST", u, nil, &key)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &key, resp, nil
+}
+
+// GetSAMLMetadata returns the SAML metadata XML of a SAML application, with
+// the signing key keyID, or the active one if keyID is empty.
+func (s *ApplicationService) GetSAMLMetadata(ctx context.Context, appID, keyID string) ([]byte, *Response, error) {
+	u, err := addOptions(appURL(appID)+"/sso/saml/metadata", &struct {
+		KeyID string `url:"kid,omitempty"`
+	}{keyID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx = WithRequestOptions(ctx, WithAccept("application/xml"))
+
+	var metadata bytes.Buffer
+	resp, err := s.client.call(ctx, "GET", u, nil, &metadata)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return metadata.Bytes(), resp, nil
+}
+
+// AppUser is the assignment of a user to an application.
+type AppUser struct {
+	ID            string                 `json:"id"`
+	ExternalID    string                 `json:"externalId"`
+	Scope         string                 `json:"scope"` // USER or GROUP
+	Status        string                 `json:"status"`
+	SyncState     string                 `json:"syncState"`
+	Credentials   *AppUserCredentials    `json:"credentials"`
+	Profile       map[string]interface{} `json:"profile"`
+	Created       time.Time              `json:"created"`
+	LastUpdated   time.Time              `json:"lastUpdated"`
+	StatusChanged time.Time              `json:"statusChanged"`
+	Links         map[string]Link        `json:"_links"`
+}
+
+// UnmarshalJSON decodes an application user, mapping null or empty timestamps
+// to the zero time.
+func (a *AppUser) UnmarshalJSON(data []byte) error {
+	type alias AppUser
+	aux := &struct {
+		*alias
+		Created       timestamp `json:"created"`
+		LastUpdated   timestamp `json:"lastUpdated"`
+		StatusChanged timestamp `json:"statusChanged"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.Created = time.Time(aux.Created)
+	a.LastUpdated = time.Time(aux.LastUpdated)
+	a.StatusChanged = time.Time(aux.StatusChanged)
+	return nil
+}
+
+// AppUserCredentials are the credentials of a user in an application,
+// such as the sign-in of a SWA application. Okta never returns the password.
+type AppUserCredentials struct {
+	UserName string              `json:"userName,omitempty"`
+	Password *PasswordCredential `json:"password,omitempty"`
+}
+
+// GetUser returns the assignment of a user to an application.
+func (s *ApplicationService) GetUser(ctx context.Context, appID, userID string) (*AppUser, *Response, error) {
+	u := fmt.Sprintf("%v/users/%v", appURL(appID), userID)
+
+	var appUser AppUser
+	resp, err := s.client.call(ctx, "GET", u, nil, &appUser)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &appUser, resp, nil
+}
+
+// UpdateUser updates the assignment of a user to an application.
+// Only the Credentials and Profile of update are sent, when set.
+func (s *ApplicationService) UpdateUser(ctx context.Context, appID, userID string, update *AppUser) (*AppUser, *Response, error) {
+	post := struct {
+		Credentials *AppUserCredentials    `json:"credentials,omitempty"`
+		Profile     map[string]interface{} `json:"profile,omitempty"`
+	}{
+		update.Credentials,
+		update.Profile,
+	}
+
+	return s.updateUser(ctx, appID, userID, post)
+}
+
+// UpdateUserCredentials only updates the credentials of a user in an
+// application, e.g. after their password changed in the application.
+func (s *ApplicationService) UpdateUserCredentials(ctx context.Context, appID, userID string, credentials *AppUserCredentials) (*AppUser, *Response, error) {
+	post := struct {
+		Credentials *AppUserCredentials `json:"credentials"`
+	}{
+		credentials,
+	}
+
+	return s.updateUser(ctx, appID, userID, post)
+}
+
+func (s *ApplicationService) updateUser(ctx context.Context, appID, userID string, body interface{}) (*AppUser, *Response, error) {
+	u := fmt.Sprintf("%v/users/%v", appURL(appID), userID)
+
+	var appUser AppUser
+	resp, err := s.client.call(ctx, "POST", u, body, &appUser)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &appUser, resp, nil
+}
+
+// ListUsers returns a page of the users assigned to an application.
+func (s *ApplicationService) ListUsers(ctx context.Context, appID string, opt *ListOptions) ([]*AppUser, *Response, error) {
+	u, err := addOptions(appURL(appID)+"/users", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var users []*AppUser
+	resp, err := s.client.call(ctx, "GET", u, nil, &users)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return users, resp, nil
+}
+
+// StreamUsers calls fn with each user assigned to an application, one page
+// at a time, so that only a page is held in memory. It stops at the first
+// error of fn, which it returns, or when ctx is done.
+func (s *ApplicationService) StreamUsers(ctx context.Context, appID string, fn func(*AppUser) error) error {
+	opt := &ListOptions{Limit: 500}
+	for {
+		page, resp, err := s.ListUsers(ctx, appID, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range page {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+
+		if opt.After = cursorOf(resp); opt.After == "" {
+			return nil
+		}
+	}
+}
+
+// AppGroup is the assignment of a group to an application.
+type AppGroup struct {
+	ID          string                 `json:"id"`
+	Priority    int                    `json:"priority"`
+	Profile     map[string]interface{} `json:"profile"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links"`
+}
+
+// UnmarshalJSON decodes an application group, mapping null or empty timestamps
+// to the zero time.
+func (a *AppGroup) UnmarshalJSON(data []byte) error {
+	type alias AppGroup
+	aux := &struct {
+		*alias
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// ListGroups returns a page of the groups assigned to an application.
+func (s *ApplicationService) ListGroups(ctx context.Context, appID string, opt *ListOptions) ([]*AppGroup, *Response, error) {
+	u, err := addOptions(appURL(appID)+"/groups", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var groups []*AppGroup
+	resp, err := s.client.call(ctx, "GET", u, nil, &groups)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return groups, resp, nil
+}
+
+// GroupsForApps returns the groups assigned to each of appIDs, by app ID,
+// listing the apps with at most concurrency requests at once.
+// Rate limited requests are only retried when the client has MaxRetries set.
+// On error, including the cancellation of ctx, the groups of the apps listed
+// so far are returned along with it.
+func (s *ApplicationService) GroupsForApps(ctx context.Context, appIDs []string, concurrency int) (map[string][]*AppGroup, error) {
+	var mu sync.Mutex
+	groups := make(map[string][]*AppGroup, len(appIDs))
+	var err error
+	forEach(ctx, len(appIDs), concurrency, func(i int) {
+		appGroups, lerr := s.allGroups(ctx, appIDs[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if lerr != nil {
+			if err == nil {
+				err = fmt.Errorf("list groups of application %v: %w", appIDs[i], lerr)
+			}
+			return
+		}
+
+		groups[appIDs[i]] = appGroups
+	})
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return groups, err
+}
+
+// allGroups returns all the groups assigned to an application.
+func (s *ApplicationService) allGroups(ctx context.Context, appID string) ([]*AppGroup, error) {
+	opt := &ListOptions{Limit: 200}
+
+	var groups []*AppGroup
+	for {
+		page, resp, err := s.ListGroups(ctx, appID, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, page...)
+		if opt.After = cursorOf(resp); opt.After == "" {
+			return groups, nil
+		}
+	}
+}
+
+// ListTokens returns a page of the OAuth refresh tokens issued for an application.
+func (s *ApplicationService) ListTokens(ctx context.Context, appID string, opt *ListOptions) ([]*RefreshToken, *Response, error) {
+	u, err := addOptions(appURL(appID)+"/tokens", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tokens []*RefreshToken
+	resp, err := s.client.call(ctx, "GET", u, nil, &tokens)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return tokens, resp, nil
+}
+
+// GetToken returns an OAuth refresh token issued for an application.
+func (s *ApplicationService) GetToken(ctx context.Context, appID, tokenID string) (*RefreshToken, *Response, error) {
+	u := fmt.Sprintf("%v/tokens/%v", appURL(appID), tokenID)
+
+	var token RefreshToken
+	resp, err := s.client.call(ctx, "GET", u, nil, &token)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &token, resp, nil
+}
+
+// RevokeToken revokes an OAuth refresh token issued for an application.
+func (s *ApplicationService) RevokeToken(ctx context.Context, appID, tokenID string) (*Response, error) {
+	u := fmt.Sprintf("%v/tokens/%v", appURL(appID), tokenID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// RevokeAllTokens revokes every OAuth refresh token issued for an application.
+func (s *ApplicationService) RevokeAllTokens(ctx context.Context, appID string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", appURL(appID)+"/tokens", nil, nil)
+}
+
+// GroupPushMapping pushes an Okta group, and its members, to a group of an
+// application.
+type GroupPushMapping struct {
+	ID            string          `json:"id"`
+	SourceGroupID string          `json:"sourceGroupId"`
+	TargetGroupID string          `json:"targetGroupId"`
+	Status        string          `json:"status"` // ACTIVE, INACTIVE or ERROR
+	ErrorSummary  string          `json:"errorSummary"`
+	Created       time.Time       `json:"created"`
+	LastUpdated   time.Time       `json:"lastUpdated"`
+	LastPush      time.Time       `json:"lastPush"`
+	Links         map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a group push mapping, mapping null or empty timestamps
+// to the zero time.
+func (g *GroupPushMapping) UnmarshalJSON(data []byte) error {
+	type alias GroupPushMapping
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+		LastPush    timestamp `json:"lastPush"`
+	}{alias: (*alias)(g)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	g.Created = time.Time(aux.Created)
+	g.LastUpdated = time.Time(aux.LastUpdated)
+	g.LastPush = time.Time(aux.LastPush)
+	return nil
+}
+
+// GroupPushMappingRequest describes a group push mapping to create. Either
+// TargetGroupID links an existing group of the application, or
+// TargetGroupName creates one.
+type GroupPushMappingRequest struct {
+	SourceGroupID   string `json:"sourceGroupId"`
+	TargetGroupID   string `json:"targetGroupId,omitempty"`
+	TargetGroupName string `json:"targetGroupName,omitempty"`
+	Status          string `json:"status,omitempty"` // ACTIVE by default
+}
+
+// ListGroupPushMappings returns the group push mappings of an application.
+func (s *ApplicationService) ListGroupPushMappings(ctx context.Context, appID string) ([]*GroupPushMapping, *Response, error) {
+	var mappings []*GroupPushMapping
+	resp, err := s.client.call(ctx, "GET", appURL(appID)+"/group-push/mappings", nil, &mappings)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return mappings, resp, nil
+}
+
+// CreateGroupPushMapping creates a group push mapping for an application.
+func (s *ApplicationService) CreateGroupPushMapping(ctx context.Context, appID string, mapping *GroupPushMappingRequest) (*GroupPushMapping, *Response, error) {
+	var created GroupPushMapping
+	resp, err := s.client.call(ctx, "POST", appURL(appID)+"/group-push/mappings", mapping, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// DeleteGroupPushMapping deletes a group push mapping of an application.
+// Okta only deletes inactive mappings. If deleteTargetGroup is true, the
+// group of the application is deleted too.
+func (s *ApplicationService) DeleteGroupPushMapping(ctx context.Context, appID, mappingID string, deleteTargetGroup bool) (*Response, error) {
+	u, err := addOptions(fmt.Sprintf("%v/group-push/mappings/%v", appURL(appID), mappingID), &struct {
+		DeleteTargetGroup bool `url:"deleteTargetGroup"`
+	}{deleteTargetGroup})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
@@ -0,0 +1,140 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuthenticatorService manages the authenticators of Identity Engine orgs,
+// which replace the factors of Classic Engine orgs for MFA.
+type AuthenticatorService service
+
+// Authenticator keys.
+const (
+	AuthenticatorKeyPassword         = "okta_password"
+	AuthenticatorKeyEmail            = "okta_email"
+	AuthenticatorKeyPhone            = "phone_number"
+	AuthenticatorKeyOktaVerify       = "okta_verify"
+	AuthenticatorKeyGoogleOTP        = "google_otp"
+	AuthenticatorKeyWebAuthn         = "webauthn"
+	AuthenticatorKeySecurityQuestion = "security_question"
+)
+
+// Authenticator is a way for users to prove their identity, such as a
+// password, an email or Okta Verify. The keys of Settings depend on Key,
+// e.g. allowedFor.
+type Authenticator struct {
+	ID          string                 `json:"id,omitempty"`
+	Key         string                 `json:"key"`
+	Type        string                 `json:"type,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Name        string                 `json:"name"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Provider    map[string]interface{} `json:"provider,omitempty"`
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes an authenticator, mapping null or empty timestamps to
+// the zero time.
+func (a *Authenticator) UnmarshalJSON(data []byte) error {
+	type alias Authenticator
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.Created = time.Time(aux.Created)
+	a.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// MarshalJSON encodes an authenticator without its read-only timestamps, which
+// would otherwise be sent as the year 1 until they are set.
+func (a Authenticator) MarshalJSON() ([]byte, error) {
+	type alias Authenticator
+	return json.Marshal(&struct {
+		*alias
+		Created     *struct{} `json:"created,omitempty"`
+		LastUpdated *struct{} `json:"lastUpdated,omitempty"`
+	}{alias: (*alias)(&a)})
+}
+
+func authenticatorURL(id string) string {
+	return fmt.Sprintf("/api/v1/authenticators/%v", id)
+}
+
+// List returns all the authenticators.
+func (s *AuthenticatorService) List(ctx context.Context) ([]*Authenticator, *Response, error) {
+	var authenticators []*Authenticator
+	resp, err := s.client.call(ctx, "GET", "/api/v1/authenticators", nil, &authenticators)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return authenticators, resp, nil
+}
+
+// Get returns an authenticator.
+func (s *AuthenticatorService) Get(ctx context.Context, id string) (*Authenticator, *Response, error) {
+	var authenticator Authenticator
+	resp, err := s.client.call(ctx, "GET", authenticatorURL(id), nil, &authenticator)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &authenticator, resp, nil
+}
+
+// Create creates an authenticator, active unless activate is false.
+func (s *AuthenticatorService) Create(ctx context.Context, authenticator *Authenticator, activate bool) (*Authenticator, *Response, error) {
+	u := fmt.Sprintf("/api/v1/authenticators?activate=%v", activate)
+
+	var created Authenticator
+	resp, err := s.client.call(ctx, "POST", u, authenticator, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update replaces an authenticator.
+func (s *AuthenticatorService) Update(ctx context.Context, id string, authenticator *Authenticator) (*Authenticator, *Response, error) {
+	var updated Authenticator
+	resp, err := s.client.call(ctx, "PUT", authenticatorURL(id), authenticator, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Activate activates an authenticator.
+func (s *AuthenticatorService) Activate(ctx context.Context, id string) (*Authenticator, *Response, error) {
+	return s.lifecycle(ctx, id, "activate")
+}
+
+// Deactivate deactivates an authenticator. Okta refuses it while the
+// authenticator is required by a policy.
+func (s *AuthenticatorService) Deactivate(ctx context.Context, id string) (*Authenticator, *Response, error) {
+	return s.lifecycle(ctx, id, "deactivate")
+}
+
+func (s *AuthenticatorService) lifecycle(ctx context.Context, id, action string) (*Authenticator, *Response, error) {
+	var authenticator Authenticator
+	resp, err := s.client.lifecycle(ctx, authenticatorURL(id), action, nil, &authenticator)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &authenticator, resp, nil
+}
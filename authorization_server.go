@@ -0,0 +1,411 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuthorizationServerService manages custom authorization servers,
+// along with their scopes, claims and policies.
+type AuthorizationServerService service
+
+// AuthorizationServer is a custom authorization server.
+type AuthorizationServer struct {
+	ID          string                          `json:"id,omitempty"`
+	Name        string                          `json:"name"`
+	Description string                          `json:"description"`
+	Audiences   []string                        `json:"audiences"`
+	Issuer      string                          `json:"issuer,omitempty"`
+	IssuerMode  string                          `json:"issuerMode,omitempty"` // ORG_URL or CUSTOM_URL
+	Status      string                          `json:"status,omitempty"`
+	Credentials *AuthorizationServerCredentials `json:"credentials,omitempty"`
+	Created     time.Time                       `json:"created"`
+	LastUpdated time.Time                       `json:"lastUpdated"`
+	Links       map[string]Link                 `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes an authorization server, mapping null or empty
+// timestamps to the zero time.
+func (a *AuthorizationServer) UnmarshalJSON(data []byte) error {
+	type alias AuthorizationServer
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.Created = time.Time(aux.Created)
+	a.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// MarshalJSON encodes an authorization server without its read-only timestamps,
+// which would otherwise be sent as the year 1 until they are set.
+func (a AuthorizationServer) MarshalJSON() ([]byte, error) {
+	type alias AuthorizationServer
+	return json.Marshal(&struct {
+		*alias
+		Created     *struct{} `json:"created,omitempty"`
+		LastUpdated *struct{} `json:"lastUpdated,omitempty"`
+	}{alias: (*alias)(&a)})
+}
+
+// AuthorizationServerCredentials describe how an authorization server signs its tokens.
+type AuthorizationServerCredentials struct {
+	Signing AuthorizationServerSigning `json:"signing"`
+}
+
+// AuthorizationServerSigning is the key an authorization server signs its
+// tokens with, and how it is rotated.
+type AuthorizationServerSigning struct {
+	Kid          string    `json:"kid,omitempty"`
+	RotationMode string    `json:"rotationMode,omitempty"` // AUTO or MANUAL
+	LastRotated  time.Time `json:"lastRotated"`
+	NextRotation time.Time `json:"nextRotation"`
+}
+
+// UnmarshalJSON decodes a signing key, mapping null or empty timestamps to
+// the zero time.
+func (a *AuthorizationServerSigning) UnmarshalJSON(data []byte) error {
+	type alias AuthorizationServerSigning
+	aux := &struct {
+		*alias
+		LastRotated  timestamp `json:"lastRotated"`
+		NextRotation timestamp `json:"nextRotation"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.LastRotated = time.Time(aux.LastRotated)
+	a.NextRotation = time.Time(aux.NextRotation)
+	return nil
+}
+
+// MarshalJSON encodes a signing key without its rotation times, which are
+// read-only.
+func (a AuthorizationServerSigning) MarshalJSON() ([]byte, error) {
+	type alias AuthorizationServerSigning
+	return json.Marshal(&struct {
+		*alias
+		LastRotated  *struct{} `json:"lastRotated,omitempty"`
+		NextRotation *struct{} `json:"nextRotation,omitempty"`
+	}{alias: (*alias)(&a)})
+}
+
+// Scope is an OAuth scope of an authorization server.
+type Scope struct {
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name"`
+	DisplayName     string `json:"displayName,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Consent         string `json:"consent,omitempty"` // REQUIRED or IMPLICIT
+	Default         bool   `json:"default"`
+	MetadataPublish string `json:"metadataPublish,omitempty"` // ALL_CLIENTS or NO_CLIENTS
+	System          bool   `json:"system,omitempty"`
+}
+
+// Claim is a claim added by an authorization server to its tokens.
+type Claim struct {
+	ID                   string           `json:"id,omitempty"`
+	Name                 string           `json:"name"`
+	Status               string           `json:"status,omitempty"`
+	ClaimType            string           `json:"claimType"` // RESOURCE (access token) or IDENTITY (ID token)
+	ValueType            string           `json:"valueType"` // EXPRESSION, GROUPS or SYSTEM
+	Value                string           `json:"value"`
+	GroupFilterType      string           `json:"group_filter_type,omitempty"`
+	AlwaysIncludeInToken bool             `json:"alwaysIncludeInToken"`
+	Conditions           *ClaimConditions `json:"conditions,omitempty"`
+	System               bool             `json:"system,omitempty"`
+}
+
+// ClaimConditions restrict when a claim is added to a token.
+type ClaimConditions struct {
+	Scopes []string `json:"scopes"`
+}
+
+// AuthorizationServerPolicy controls which clients can get tokens from an authorization server.
+type AuthorizationServerPolicy struct {
+	ID          string                               `json:"id,omitempty"`
+	Type        string                               `json:"type"` // OAUTH_AUTHORIZATION_POLICY
+	Name        string                               `json:"name"`
+	Description string                               `json:"description"`
+	Status      string                               `json:"status,omitempty"`
+	Priority    int                                  `json:"priority,omitempty"`
+	System      bool                                 `json:"system,omitempty"`
+	Conditions  *AuthorizationServerPolicyConditions `json:"conditions,omitempty"`
+	Created     time.Time                            `json:"created"`
+	LastUpdated time.Time                            `json:"lastUpdated"`
+}
+
+// UnmarshalJSON decodes an authorization server policy, mapping null or empty
+// timestamps to the zero time.
+func (a *AuthorizationServerPolicy) UnmarshalJSON(data []byte) error {
+	type alias AuthorizationServerPolicy
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.Created = time.Time(aux.Created)
+	a.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// MarshalJSON encodes an authorization server policy without its read-only
+// timestamps, which would otherwise be sent as the year 1 until they are set.
+func (a AuthorizationServerPolicy) MarshalJSON() ([]byte, error) {
+	type alias AuthorizationServerPolicy
+	return json.Marshal(&struct {
+		*alias
+		Created     *struct{} `json:"created,omitempty"`
+		LastUpdated *struct{} `json:"lastUpdated,omitempty"`
+	}{alias: (*alias)(&a)})
+}
+
+// AuthorizationServerPolicyConditions restrict the clients a policy applies to.
+type AuthorizationServerPolicyConditions struct {
+	Clients struct {
+		Include []string `json:"include"` // client IDs, or ALL_CLIENTS
+	} `json:"clients"`
+}
+
+func authorizationServerURL(id string) string {
+	return fmt.Sprintf("/api/v1/authorizationServers/%v", id)
+}
+
+// List returns all the authorization servers.
+func (s *AuthorizationServerService) List(ctx context.Context) ([]*AuthorizationServer, *Response, error) {
+	var servers []*AuthorizationServer
+	resp, err := s.client.call(ctx, "GET", "/api/v1/authorizationServers", nil, &servers)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return servers, resp, nil
+}
+
+// Get returns an authorization server.
+func (s *AuthorizationServerService) Get(ctx context.Context, id string) (*AuthorizationServer, *Response, error) {
+	var server AuthorizationServer
+	resp, err := s.client.call(ctx, "GET", authorizationServerURL(id), nil, &server)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &server, resp, nil
+}
+
+// Create creates an authorization server.
+func (s *AuthorizationServerService) Create(ctx context.Context, server *AuthorizationServer) (*AuthorizationServer, *Response, error) {
+	var created AuthorizationServer
+	resp, err := s.client.call(ctx, "POST", "/api/v1/authorizationServers", server, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update replaces an authorization server.
+func (s *AuthorizationServerService) Update(ctx context.Context, id string, server *AuthorizationServer) (*AuthorizationServer, *Response, error) {
+	var updated AuthorizationServer
+	resp, err := s.client.call(ctx, "PUT", authorizationServerURL(id), server, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Delete deletes an authorization server.
+func (s *AuthorizationServerService) Delete(ctx context.Context, id string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", authorizationServerURL(id), nil, nil)
+}
+
+// Activate activates an authorization server.
+func (s *AuthorizationServerService) Activate(ctx context.Context, id string) (*Response, error) {
+	return s.client.lifecycle(ctx, authorizationServerURL(id), "activate", nil, nil)
+}
+
+// Deactivate deactivates an authorization server.
+func (s *AuthorizationServerService) Deactivate(ctx context.Context, id string) (*Response, error) {
+	return s.client.lifecycle(ctx, authorizationServerURL(id), "deactivate", nil, nil)
+}
+
+// ListScopes returns the scopes of an authorization server.
+func (s *AuthorizationServerService) ListScopes(ctx context.Context, serverID string) ([]*Scope, *Response, error) {
+	var scopes []*Scope
+	resp, err := s.client.call(ctx, "GET", authorizationServerURL(serverID)+"/scopes", nil, &scopes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return scopes, resp, nil
+}
+
+// GetScope returns a scope of an authorization server.
+func (s *AuthorizationServerService) GetScope(ctx context.Context, serverID, scopeID string) (*Scope, *Response, error) {
+	u := fmt.Sprintf("%v/scopes/%v", authorizationServerURL(serverID), scopeID)
+
+	var scope Scope
+	resp, err := s.client.call(ctx, "GET", u, nil, &scope)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &scope, resp, nil
+}
+
+// CreateScope creates a scope on an authorization server.
+func (s *AuthorizationServerService) CreateScope(ctx context.Context, serverID string, scope *Scope) (*Scope, *Response, error) {
+	var created Scope
+	resp, err := s.client.call(ctx, "POST", authorizationServerURL(serverID)+"/scopes", scope, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// UpdateScope replaces a scope of an authorization server.
+func (s *AuthorizationServerService) UpdateScope(ctx context.Context, serverID, scopeID string, scope *Scope) (*Scope, *Response, error) {
+	u := fmt.Sprintf("%v/scopes/%v", authorizationServerURL(serverID), scopeID)
+
+	var updated Scope
+	resp, err := s.client.call(ctx, "PUT", u, scope, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// DeleteScope deletes a scope of an authorization server.
+func (s *AuthorizationServerService) DeleteScope(ctx context.Context, serverID, scopeID string) (*Response, error) {
+	u := fmt.Sprintf("%v/scopes/%v", authorizationServerURL(serverID), scopeID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// ListClaims returns the claims of an authorization server.
+func (s *AuthorizationServerService) ListClaims(ctx context.Context, serverID string) ([]*Claim, *Response, error) {
+	var claims []*Claim
+	resp, err := s.client.call(ctx, "GET", authorizationServerURL(serverID)+"/claims", nil, &claims)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return claims, resp, nil
+}
+
+// GetClaim returns a claim of an authorization server.
+func (s *AuthorizationServerService) GetClaim(ctx context.Context, serverID, claimID string) (*Claim, *Response, error) {
+	u := fmt.Sprintf("%v/claims/%v", authorizationServerURL(serverID), claimID)
+
+	var claim Claim
+	resp, err := s.client.call(ctx, "GET", u, nil, &claim)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &claim, resp, nil
+}
+
+// CreateClaim creates a claim on an authorization server.
+func (s *AuthorizationServerService) CreateClaim(ctx context.Context, serverID string, claim *Claim) (*Claim, *Response, error) {
+	var created Claim
+	resp, err := s.client.call(ctx, "POST", authorizationServerURL(serverID)+"/claims", claim, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// UpdateClaim replaces a claim of an authorization server.
+func (s *AuthorizationServerService) UpdateClaim(ctx context.Context, serverID, claimID string, claim *Claim) (*Claim, *Response, error) {
+	u := fmt.Sprintf("%v/claims/%v", authorizationServerURL(serverID), claimID)
+
+	var updated Claim
+	resp, err := s.client.call(ctx, "PUT", u, claim, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// DeleteClaim deletes a claim of an authorization server.
+func (s *AuthorizationServerService) DeleteClaim(ctx context.Context, serverID, claimID string) (*Response, error) {
+	u := fmt.Sprintf("%v/claims/%v", authorizationServerURL(serverID), claimID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// ListPolicies returns the policies of an authorization server.
+func (s *AuthorizationServerService) ListPolicies(ctx context.Context, serverID string) ([]*AuthorizationServerPolicy, *Response, error) {
+	var policies []*AuthorizationServerPolicy
+	resp, err := s.client.call(ctx, "GET", authorizationServerURL(serverID)+"/policies", nil, &policies)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return policies, resp, nil
+}
+
+// GetPolicy returns a policy of an authorization server.
+func (s *AuthorizationServerService) GetPolicy(ctx context.Context, serverID, policyID string) (*AuthorizationServerPolicy, *Response, error) {
+	u := fmt.Sprintf("%v/policies/%v", authorizationServerURL(serverID), policyID)
+
+	var policy AuthorizationServerPolicy
+	resp, err := s.client.call(ctx, "GET", u, nil, &policy)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &policy, resp, nil
+}
+
+// CreatePolicy creates a policy on an authorization server.
+func (s *AuthorizationServerService) CreatePolicy(ctx context.Context, serverID string, policy *AuthorizationServerPolicy) (*AuthorizationServerPolicy, *Response, error) {
+	var created AuthorizationServerPolicy
+	resp, err := s.client.call(ctx, "POST", authorizationServerURL(serverID)+"/policies", policy, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// UpdatePolicy replaces a policy of an authorization server.
+func (s *AuthorizationServerService) UpdatePolicy(ctx context.Context, serverID, policyID string, policy *AuthorizationServerPolicy) (*AuthorizationServerPolicy, *Response, error) {
+	u := fmt.Sprintf("%v/policies/%v", authorizationServerURL(serverID), policyID)
+
+	var updated AuthorizationServerPolicy
+	resp, err := s.client.call(ctx, "PUT", u, policy, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// DeletePolicy deletes a policy of an authorization server.
+func (s *AuthorizationServerService) DeletePolicy(ctx context.Context, serverID, policyID string) (*Response, error) {
+	u := fmt.Sprintf("%v/policies/%v", authorizationServerURL(serverID), policyID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
@@ -0,0 +1,102 @@
+package okta
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"sync"
+)
+
+// BatchService runs bulk operations built on the other services.
+type BatchService service
+
+// CSVColumnMapping maps the columns of a CSV file, by header, to the profile
+// attributes they hold, e.g. {"E-mail": "email"}. Other columns are ignored.
+type CSVColumnMapping map[string]string
+
+// ImportSummary is the result of ImportCSV.
+type ImportSummary struct {
+	Created int
+	Failed  int
+
+	// Rows holds the result of each data row, in file order.
+	Rows []*ImportRow
+}
+
+// ImportRow is the result of importing a row of a CSV file.
+type ImportRow struct {
+	// Line is the line of the row in the file, the header being line 1.
+	Line   int
+	UserID string
+	Err    error
+}
+
+// importConcurrency is the number of users ImportCSV creates at once.
+const importConcurrency = 4
+
+// ImportCSV creates a user, without activating it, for each data row of the
+// CSV file read from r, whose first row is the header. Profile attributes are
+// set from the columns per mapping, empty cells being skipped. Rows are
+// imported concurrently; rate limited requests are only retried when the
+// client has MaxRetries set. An error is returned when r isn't a valid CSV
+// file or lacks a mapped column; failed rows are reported in the summary.
+func (s *BatchService) ImportCSV(ctx context.Context, r io.Reader, mapping CSVColumnMapping) (*ImportSummary, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("okta: CSV file has no header")
+	}
+
+	columns := make(map[int]string, len(mapping))
+	for i, name := range records[0] {
+		if attr, ok := mapping[name]; ok {
+			columns[i] = attr
+		}
+	}
+	if len(columns) != len(mapping) {
+		return nil, errors.New("okta: CSV file lacks some of the mapped columns")
+	}
+
+	rows := records[1:]
+	summary := &ImportSummary{Rows: make([]*ImportRow, len(rows))}
+	for i := range rows {
+		summary.Rows[i] = &ImportRow{Line: i + 2}
+	}
+
+	var mu sync.Mutex
+	forEach(ctx, len(rows), importConcurrency, func(i int) {
+		profile := make(map[string]interface{}, len(columns))
+		for col, attr := range columns {
+			if v := rows[i][col]; v != "" {
+				profile[attr] = v
+			}
+		}
+
+		user, _, err := s.client.User.Create(ctx, &CreateUserRequest{Profile: profile}, false)
+
+		mu.Lock()
+		defer mu.Unlock()
+		summary.Rows[i].Err = err
+		if err == nil {
+			summary.Rows[i].UserID = user.ID
+		}
+	})
+
+	for _, row := range summary.Rows {
+		// Rows not started once ctx was done.
+		if row.Err == nil && row.UserID == "" {
+			row.Err = ctx.Err()
+		}
+
+		if row.Err != nil {
+			summary.Failed++
+		} else {
+			summary.Created++
+		}
+	}
+
+	return summary, nil
+}
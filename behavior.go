@@ -0,0 +1,135 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BehaviorService manages the behavior detection rules referenced by sign-on policies.
+type BehaviorService service
+
+// Behavior detection rule types.
+const (
+	BehaviorTypeAnomalousLocation = "ANOMALOUS_LOCATION"
+	BehaviorTypeAnomalousDevice   = "ANOMALOUS_DEVICE"
+	BehaviorTypeAnomalousIP       = "ANOMALOUS_IP"
+	BehaviorTypeVelocity          = "VELOCITY"
+)
+
+// BehaviorRule is a behavior detection rule.
+// The keys of Settings depend on Type, e.g. maxEventsUsedForEvaluation.
+type BehaviorRule struct {
+	ID          string                 `json:"id,omitempty"`
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name"`
+	Status      string                 `json:"status,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes a behavior rule, mapping null or empty timestamps to
+// the zero time.
+func (b *BehaviorRule) UnmarshalJSON(data []byte) error {
+	type alias BehaviorRule
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(b)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	b.Created = time.Time(aux.Created)
+	b.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// MarshalJSON encodes a behavior rule without its read-only timestamps, which
+// would otherwise be sent as the year 1 until they are set.
+func (b BehaviorRule) MarshalJSON() ([]byte, error) {
+	type alias BehaviorRule
+	return json.Marshal(&struct {
+		*alias
+		Created     *struct{} `json:"created,omitempty"`
+		LastUpdated *struct{} `json:"lastUpdated,omitempty"`
+	}{alias: (*alias)(&b)})
+}
+
+func behaviorURL(id string) string {
+	return fmt.Sprintf("/api/v1/behaviors/%v", id)
+}
+
+// List returns all the behavior detection rules.
+func (s *BehaviorService) List(ctx context.Context) ([]*BehaviorRule, *Response, error) {
+	var rules []*BehaviorRule
+	resp, err := s.client.call(ctx, "GET", "/api/v1/behaviors", nil, &rules)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rules, resp, nil
+}
+
+// Get returns a behavior detection rule.
+func (s *BehaviorService) Get(ctx context.Context, id string) (*BehaviorRule, *Response, error) {
+	var rule BehaviorRule
+	resp, err := s.client.call(ctx, "GET", behaviorURL(id), nil, &rule)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &rule, resp, nil
+}
+
+// Create creates a behavior detection rule.
+func (s *BehaviorService) Create(ctx context.Context, rule *BehaviorRule) (*BehaviorRule, *Response, error) {
+	var created BehaviorRule
+	resp, err := s.client.call(ctx, "POST", "/api/v1/behaviors", rule, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update replaces a behavior detection rule.
+func (s *BehaviorService) Update(ctx context.Context, id string, rule *BehaviorRule) (*BehaviorRule, *Response, error) {
+	var updated BehaviorRule
+	resp, err := s.client.call(ctx, "PUT", behaviorURL(id), rule, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Delete deletes a behavior detection rule.
+func (s *BehaviorService) Delete(ctx context.Context, id string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", behaviorURL(id), nil, nil)
+}
+
+// Activate activates a behavior detection rule.
+func (s *BehaviorService) Activate(ctx context.Context, id string) (*BehaviorRule, *Response, error) {
+	return s.lifecycle(ctx, id, "activate")
+}
+
+// Deactivate deactivates a behavior detection rule.
+func (s *BehaviorService) Deactivate(ctx context.Context, id string) (*BehaviorRule, *Response, error) {
+	return s.lifecycle(ctx, id, "deactivate")
+}
+
+func (s *BehaviorService) lifecycle(ctx context.Context, id, action string) (*BehaviorRule, *Response, error) {
+	var rule BehaviorRule
+	resp, err := s.client.lifecycle(ctx, behaviorURL(id), action, nil, &rule)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &rule, resp, nil
+}
@@ -0,0 +1,70 @@
+package okta
+
+import (
+	"sync"
+	"time"
+)
+
+// UserCache caches the users fetched by UserService.GetUser, see
+// Client.UserCache. Implementations must be safe for concurrent use.
+type UserCache interface {
+	// Get returns the cached user id, and whether it was found.
+	Get(id string) (*User, bool)
+
+	// Set caches user as the user id.
+	Set(id string, user *User)
+
+	// Delete evicts the user id, after a change made through the client.
+	Delete(id string)
+}
+
+// NewUserCache returns an in-memory UserCache whose users expire ttl after
+// being cached. Expired users are evicted when they are looked up again.
+func NewUserCache(ttl time.Duration) UserCache {
+	return &userCache{
+		ttl:   ttl,
+		users: make(map[string]cachedUser),
+	}
+}
+
+type userCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	users map[string]cachedUser
+}
+
+type cachedUser struct {
+	user    *User
+	expires time.Time
+}
+
+func (c *userCache) Get(id string) (*User, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.users[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(cached.expires) {
+		delete(c.users, id)
+		return nil, false
+	}
+
+	return cached.user, true
+}
+
+func (c *userCache) Set(id string, user *User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.users[id] = cachedUser{user: user, expires: time.Now().Add(c.ttl)}
+}
+
+func (c *userCache) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.users, id)
+}
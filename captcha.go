@@ -0,0 +1,120 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+)
+
+// CaptchaService manages the CAPTCHA instances of the organisation, and which
+// one protects its pages.
+type CaptchaService service
+
+// CAPTCHA types.
+const (
+	CaptchaTypeHCaptcha    = "HCAPTCHA"
+	CaptchaTypeReCaptchaV2 = "RECAPTCHA_V2"
+)
+
+// Captcha is a CAPTCHA instance, the account of the organisation at a CAPTCHA
+// provider. Okta never returns its SecretKey.
+type Captcha struct {
+	ID        string          `json:"id,omitempty"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	SiteKey   string          `json:"siteKey"`
+	SecretKey string          `json:"secretKey,omitempty"`
+	Links     map[string]Link `json:"_links,omitempty"`
+}
+
+// CaptchaSettings are the CAPTCHA settings of the organisation: the instance
+// CaptchaID protects the EnabledPages, among SIGN_IN, SSR (self-service
+// registration) and SSPR (self-service password reset).
+type CaptchaSettings struct {
+	CaptchaID    string          `json:"captchaId"`
+	EnabledPages []string        `json:"enabledPages"`
+	Links        map[string]Link `json:"_links,omitempty"`
+}
+
+func captchaURL(id string) string {
+	return fmt.Sprintf("/api/v1/captchas/%v", id)
+}
+
+// List returns the CAPTCHA instances.
+func (s *CaptchaService) List(ctx context.Context) ([]*Captcha, *Response, error) {
+	var captchas []*Captcha
+	resp, err := s.client.call(ctx, "GET", "/api/v1/captchas", nil, &captchas)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return captchas, resp, nil
+}
+
+// Get returns a CAPTCHA instance.
+func (s *CaptchaService) Get(ctx context.Context, id string) (*Captcha, *Response, error) {
+	var captcha Captcha
+	resp, err := s.client.call(ctx, "GET", captchaURL(id), nil, &captcha)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &captcha, resp, nil
+}
+
+// Create creates a CAPTCHA instance.
+func (s *CaptchaService) Create(ctx context.Context, captcha *Captcha) (*Captcha, *Response, error) {
+	var created Captcha
+	resp, err := s.client.call(ctx, "POST", "/api/v1/captchas", captcha, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update replaces a CAPTCHA instance, e.g. to rotate its keys.
+// SecretKey is required.
+func (s *CaptchaService) Update(ctx context.Context, id string, captcha *Captcha) (*Captcha, *Response, error) {
+	var updated Captcha
+	resp, err := s.client.call(ctx, "PUT", captchaURL(id), captcha, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Delete deletes a CAPTCHA instance. Okta refuses it while the instance is
+// used by the CAPTCHA settings of the organisation.
+func (s *CaptchaService) Delete(ctx context.Context, id string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", captchaURL(id), nil, nil)
+}
+
+// GetSettings returns the CAPTCHA settings of the organisation.
+func (s *CaptchaService) GetSettings(ctx context.Context) (*CaptchaSettings, *Response, error) {
+	var settings CaptchaSettings
+	resp, err := s.client.call(ctx, "GET", "/api/v1/org/captcha", nil, &settings)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &settings, resp, nil
+}
+
+// UpdateSettings replaces the CAPTCHA settings of the organisation, binding a
+// CAPTCHA instance to its pages.
+func (s *CaptchaService) UpdateSettings(ctx context.Context, settings *CaptchaSettings) (*CaptchaSettings, *Response, error) {
+	var updated CaptchaSettings
+	resp, err := s.client.call(ctx, "PUT", "/api/v1/org/captcha", settings, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// DeleteSettings removes the CAPTCHA settings of the organisation, so that no
+// page is protected by a CAPTCHA anymore.
+func (s *CaptchaService) DeleteSettings(ctx context.Context) (*Response, error) {
+	return s.client.call(ctx, "DELETE", "/api/v1/org/captcha", nil, nil)
+}
@@ -0,0 +1,56 @@
+package okta
+
+import (
+	"context"
+	"sync"
+)
+
+// forEach calls fn with each index in [0, n), with at most limit calls running
+// concurrently. Once ctx is done no new call is started; forEach always waits
+// for the running calls to return.
+func forEach(ctx context.Context, n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case <-ctx.Done():
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// limiter bounds the number of requests of a Client in flight at once.
+// Its semaphore is sized on first use.
+type limiter struct {
+	once sync.Once
+	sem  chan struct{}
+}
+
+// acquire waits for one of n slots to be free, or for ctx to be done.
+// The returned func releases the slot.
+func (l *limiter) acquire(ctx context.Context, n int) (func(), error) {
+	l.once.Do(func() {
+		l.sem = make(chan struct{}, n)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l.sem <- struct{}{}:
+		return func() { <-l.sem }, nil
+	}
+}
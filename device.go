@@ -0,0 +1,51 @@
+package okta
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Device is a device registered with Okta, e.g. through Okta Verify.
+type Device struct {
+	ID           string          `json:"id"`
+	Status       string          `json:"status"`
+	ResourceType string          `json:"resourceType"`
+	Profile      DeviceProfile   `json:"profile"`
+	Created      time.Time       `json:"created"`
+	LastUpdated  time.Time       `json:"lastUpdated"`
+	Links        map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a device, mapping null or empty timestamps to the zero
+// time.
+func (d *Device) UnmarshalJSON(data []byte) error {
+	type alias Device
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(d)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	d.Created = time.Time(aux.Created)
+	d.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// DeviceProfile describes a device.
+type DeviceProfile struct {
+	DisplayName           string `json:"displayName"`
+	Platform              string `json:"platform"` // ANDROID, IOS, MACOS or WINDOWS
+	Manufacturer          string `json:"manufacturer"`
+	Model                 string `json:"model"`
+	OSVersion             string `json:"osVersion"`
+	SerialNumber          string `json:"serialNumber"`
+	UDID                  string `json:"udid"`
+	SID                   string `json:"sid"`
+	Registered            bool   `json:"registered"`
+	SecureHardwarePresent bool   `json:"secureHardwarePresent"`
+	DiskEncryptionType    string `json:"diskEncryptionType"`
+}
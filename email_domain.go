@@ -0,0 +1,95 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+)
+
+// EmailDomainService manages the custom domains Okta sends emails from.
+type EmailDomainService service
+
+// Email domain validation statuses.
+const (
+	EmailDomainStatusNotStarted = "NOT_STARTED"
+	EmailDomainStatusPolling    = "POLLING"
+	EmailDomainStatusVerified   = "VERIFIED"
+	EmailDomainStatusCompleted  = "COMPLETED"
+	EmailDomainStatusDeleted    = "DELETED"
+)
+
+// EmailDomain is a custom domain Okta sends emails from, as
+// UserName@Domain. It can only be used once its DNSValidationRecords are
+// published and verified, see Verify.
+type EmailDomain struct {
+	ID                   string                  `json:"id,omitempty"`
+	Domain               string                  `json:"domain"`
+	DisplayName          string                  `json:"displayName"`
+	UserName             string                  `json:"userName"`
+	BrandID              string                  `json:"brandId,omitempty"` // only sent by Create
+	ValidationStatus     string                  `json:"validationStatus,omitempty"`
+	DNSValidationRecords []*EmailDomainDNSRecord `json:"dnsValidationRecords,omitempty"`
+	Links                map[string]Link         `json:"_links,omitempty"`
+}
+
+// EmailDomainDNSRecord is a DNS record to publish to verify an EmailDomain.
+type EmailDomainDNSRecord struct {
+	RecordType        string `json:"recordType"` // TXT or CNAME
+	FQDN              string `json:"fqdn"`
+	VerificationValue string `json:"verificationValue"`
+	Expiration        string `json:"expiration,omitempty"`
+}
+
+func emailDomainURL(id string) string {
+	return fmt.Sprintf("/api/v1/email-domains/%v", id)
+}
+
+// List returns the email domains of the organisation.
+func (s *EmailDomainService) List(ctx context.Context) ([]*EmailDomain, *Response, error) {
+	var domains []*EmailDomain
+	resp, err := s.client.call(ctx, "GET", "/api/v1/email-domains", nil, &domains)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return domains, resp, nil
+}
+
+// Get returns an email domain.
+func (s *EmailDomainService) Get(ctx context.Context, id string) (*EmailDomain, *Response, error) {
+	var domain EmailDomain
+	resp, err := s.client.call(ctx, "GET", emailDomainURL(id), nil, &domain)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &domain, resp, nil
+}
+
+// Create creates an email domain for the brand domain.BrandID. The returned
+// domain has the DNS records to publish before calling Verify.
+func (s *EmailDomainService) Create(ctx context.Context, domain *EmailDomain) (*EmailDomain, *Response, error) {
+	var created EmailDomain
+	resp, err := s.client.call(ctx, "POST", "/api/v1/email-domains", domain, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Verify makes Okta check the DNS records of an email domain. The returned
+// ValidationStatus is VERIFIED once they are found.
+func (s *EmailDomainService) Verify(ctx context.Context, id string) (*EmailDomain, *Response, error) {
+	var domain EmailDomain
+	resp, err := s.client.call(ctx, "POST", emailDomainURL(id)+"/verify", nil, &domain)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &domain, resp, nil
+}
+
+// Delete deletes an email domain.
+func (s *EmailDomainService) Delete(ctx context.Context, id string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", emailDomainURL(id), nil, nil)
+}
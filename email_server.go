@@ -0,0 +1,90 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+)
+
+// EmailServerService manages the custom SMTP servers Okta sends emails through.
+type EmailServerService service
+
+// EmailServer is a custom SMTP server. Okta never returns its Password.
+type EmailServer struct {
+	ID       string `json:"id,omitempty"`
+	Alias    string `json:"alias"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Enabled  bool   `json:"enabled"`
+}
+
+func emailServerURL(id string) string {
+	return fmt.Sprintf("/api/v1/email-servers/%v", id)
+}
+
+// List returns the email servers of the organisation.
+func (s *EmailServerService) List(ctx context.Context) ([]*EmailServer, *Response, error) {
+	var list struct {
+		EmailServers []*EmailServer `json:"email-servers"`
+	}
+	resp, err := s.client.call(ctx, "GET", "/api/v1/email-servers", nil, &list)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return list.EmailServers, resp, nil
+}
+
+// Get returns an email server.
+func (s *EmailServerService) Get(ctx context.Context, id string) (*EmailServer, *Response, error) {
+	var server EmailServer
+	resp, err := s.client.call(ctx, "GET", emailServerURL(id), nil, &server)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &server, resp, nil
+}
+
+// Create creates an email server. Only one can be enabled at a time.
+func (s *EmailServerService) Create(ctx context.Context, server *EmailServer) (*EmailServer, *Response, error) {
+	var created EmailServer
+	resp, err := s.client.call(ctx, "POST", "/api/v1/email-servers", server, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update updates an email server with the settings of server.
+// The password is kept when server.Password is empty.
+func (s *EmailServerService) Update(ctx context.Context, id string, server *EmailServer) (*EmailServer, *Response, error) {
+	var updated EmailServer
+	resp, err := s.client.call(ctx, "PATCH", emailServerURL(id), server, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Delete deletes an email server.
+func (s *EmailServerService) Delete(ctx context.Context, id string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", emailServerURL(id), nil, nil)
+}
+
+// Test sends a test email from the address from to the address to through
+// an email server.
+func (s *EmailServerService) Test(ctx context.Context, id, from, to string) (*Response, error) {
+	post := struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}{
+		from,
+		to,
+	}
+
+	return s.client.call(ctx, "POST", emailServerURL(id)+"/test", post, nil)
+}
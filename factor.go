@@ -0,0 +1,428 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+)
+
+// FactorService manages the MFA factors of users.
+type FactorService service
+
+// FactorType is the type of a Factor.
+type FactorType string
+
+// Factor types.
+const (
+	FactorTypeQuestion FactorType = "question"
+	FactorTypeSMS      FactorType = "sms"
+	FactorTypeCall     FactorType = "call"
+	FactorTypeEmail    FactorType = "email"
+	FactorTypePush     FactorType = "push"
+	FactorTypeTOTP     FactorType = "token:software:totp"
+	FactorTypeHOTP     FactorType = "token:hotp"
+	FactorTypeToken    FactorType = "token"
+	FactorTypeHardware FactorType = "token:hardware"
+	FactorTypeWeb      FactorType = "web"
+	FactorTypeU2F      FactorType = "u2f"
+	FactorTypeWebAuthn FactorType = "webauthn"
+)
+
+// FactorProvider is the provider of a Factor.
+type FactorProvider string
+
+// Factor providers.
+const (
+	FactorProviderOkta     FactorProvider = "OKTA"
+	FactorProviderGoogle   FactorProvider = "GOOGLE"
+	FactorProviderRSA      FactorProvider = "RSA"
+	FactorProviderSymantec FactorProvider = "SYMANTEC"
+	FactorProviderYubico   FactorProvider = "YUBICO"
+	FactorProviderDuo      FactorProvider = "DUO"
+	FactorProviderFIDO     FactorProvider = "FIDO"
+	FactorProviderCustom   FactorProvider = "CUSTOM"
+)
+
+// factorProviders lists the providers of each factor type.
+var factorProviders = map[FactorType][]FactorProvider{
+	FactorTypeQuestion: {FactorProviderOkta},
+	FactorTypeSMS:      {FactorProviderOkta},
+	FactorTypeCall:     {FactorProviderOkta},
+	FactorTypeEmail:    {FactorProviderOkta},
+	FactorTypePush:     {FactorProviderOkta},
+	FactorTypeTOTP:     {FactorProviderOkta, FactorProviderGoogle},
+	FactorTypeHOTP:     {FactorProviderCustom},
+	FactorTypeToken:    {FactorProviderRSA, FactorProviderSymantec},
+	FactorTypeHardware: {FactorProviderYubico},
+	FactorTypeWeb:      {FactorProviderDuo},
+	FactorTypeU2F:      {FactorProviderFIDO},
+	FactorTypeWebAuthn: {FactorProviderFIDO},
+}
+
+// validateFactor checks that provider offers factorType.
+func validateFactor(factorType FactorType, provider FactorProvider) error {
+	providers, ok := factorProviders[factorType]
+	if !ok {
+		return fmt.Errorf("okta: unknown factor type %q", factorType)
+	}
+
+	for _, p := range providers {
+		if p == provider {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("okta: factor type %q isn't offered by provider %q", factorType, provider)
+}
+
+// Factor is an MFA factor of a user.
+type Factor struct {
+	ID          string                 `json:"id"`
+	FactorType  FactorType             `json:"factorType"`
+	Provider    FactorProvider         `json:"provider"`
+	Status      string                 `json:"status"`
+	Enrollment  string                 `json:"enrollment"` // REQUIRED or OPTIONAL, only set by ListSupported
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Profile     map[string]interface{} `json:"profile"`
+	Links       map[string]Link        `json:"_links"`
+}
+
+// UnmarshalJSON decodes a factor, mapping null or empty timestamps to the zero
+// time.
+func (f *Factor) UnmarshalJSON(data []byte) error {
+	type alias Factor
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(f)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	f.Created = time.Time(aux.Created)
+	f.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// PhoneProfile returns the Profile of an SMS or call factor, and false for
+// the other factor types.
+func (f *Factor) PhoneProfile() (*PhoneFactorProfile, bool) {
+	if f.FactorType != FactorTypeSMS && f.FactorType != FactorTypeCall {
+		return nil, false
+	}
+
+	var profile PhoneFactorProfile
+	if err := decodeAs(f.Profile, &profile); err != nil {
+		return nil, false
+	}
+
+	return &profile, true
+}
+
+// QuestionProfile returns the Profile of a security question factor, and
+// false for the other factor types.
+func (f *Factor) QuestionProfile() (*QuestionFactorProfile, bool) {
+	if f.FactorType != FactorTypeQuestion {
+		return nil, false
+	}
+
+	var profile QuestionFactorProfile
+	if err := decodeAs(f.Profile, &profile); err != nil {
+		return nil, false
+	}
+
+	return &profile, true
+}
+
+// EmailProfile returns the Profile of an email factor, and false for the
+// other factor types.
+func (f *Factor) EmailProfile() (*EmailFactorProfile, bool) {
+	if f.FactorType != FactorTypeEmail {
+		return nil, false
+	}
+
+	var profile EmailFactorProfile
+	if err := decodeAs(f.Profile, &profile); err != nil {
+		return nil, false
+	}
+
+	return &profile, true
+}
+
+// PhoneFactorProfile is the profile of an SMS or call factor.
+type PhoneFactorProfile struct {
+	PhoneNumber    string `json:"phoneNumber"`
+	PhoneExtension string `json:"phoneExtension,omitempty"`
+}
+
+// QuestionFactorProfile is the profile of a security question factor.
+// Okta never returns the Answer.
+type QuestionFactorProfile struct {
+	Question     string `json:"question"`
+	QuestionText string `json:"questionText"`
+	Answer       string `json:"answer,omitempty"`
+}
+
+// EmailFactorProfile is the profile of an email factor.
+type EmailFactorProfile struct {
+	Email string `json:"email"`
+}
+
+// NewQuestionFactor returns a security question factor to enroll, see
+// ListSupportedSecurityQuestions for the available questions.
+func NewQuestionFactor(question, answer string) *Factor {
+	return &Factor{
+		FactorType: FactorTypeQuestion,
+		Provider:   FactorProviderOkta,
+		Profile: map[string]interface{}{
+			"question": question,
+			"answer":   answer,
+		},
+	}
+}
+
+// SecurityQuestion is a question available for the security question factor.
+type SecurityQuestion struct {
+	Question     string `json:"question"`
+	QuestionText string `json:"questionText"`
+}
+
+// FactorResult is the outcome of a factor verification.
+type FactorResult string
+
+// Factor verification results.
+const (
+	FactorResultSuccess            FactorResult = "SUCCESS"
+	FactorResultChallenge          FactorResult = "CHALLENGE"
+	FactorResultWaiting            FactorResult = "WAITING"
+	FactorResultFailed             FactorResult = "FAILED"
+	FactorResultRejected           FactorResult = "REJECTED"
+	FactorResultTimeout            FactorResult = "TIMEOUT"
+	FactorResultTimeWindowExceeded FactorResult = "TIME_WINDOW_EXCEEDED"
+	FactorResultPasscodeReplayed   FactorResult = "PASSCODE_REPLAYED"
+	FactorResultCancelled          FactorResult = "CANCELLED"
+	FactorResultError              FactorResult = "ERROR"
+)
+
+// FactorVerifyResponse is the result of a factor verification.
+// Verifying a push factor is asynchronous: FactorResult is WAITING until the
+// user answers, see PollTransaction.
+type FactorVerifyResponse struct {
+	FactorResult        FactorResult    `json:"factorResult"`
+	FactorResultMessage string          `json:"factorResultMessage"`
+	ExpiresAt           time.Time       `json:"expiresAt"`
+	Links               map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a verification, mapping null or empty timestamps to the
+// zero time.
+func (f *FactorVerifyResponse) UnmarshalJSON(data []byte) error {
+	type alias FactorVerifyResponse
+	aux := &struct {
+		*alias
+		ExpiresAt timestamp `json:"expiresAt"`
+	}{alias: (*alias)(f)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	f.ExpiresAt = time.Time(aux.ExpiresAt)
+	return nil
+}
+
+// IsSuccess reports whether the factor is verified.
+func (r *FactorVerifyResponse) IsSuccess() bool {
+	return r.FactorResult == FactorResultSuccess
+}
+
+// IsPending reports whether the verification isn't over yet: Okta either
+// waits for the user to answer a push, or has sent a challenge, such as an
+// SMS code, to verify with another call. Any other result than SUCCESS
+// ends the verification.
+func (r *FactorVerifyResponse) IsPending() bool {
+	return r.FactorResult == FactorResultWaiting || r.FactorResult == FactorResultChallenge
+}
+
+// TransactionID returns the ID of the verification transaction to poll,
+// or an empty string if the verification isn't asynchronous.
+func (r *FactorVerifyResponse) TransactionID() string {
+	poll, ok := r.Links["poll"]
+	if !ok || poll.Href == "" {
+		return ""
+	}
+
+	return path.Base(poll.Href)
+}
+
+// VerifyFactor verifies a factor of a user. passCode is the code entered by
+// the user, and is empty for push factors.
+func (s *FactorService) VerifyFactor(ctx context.Context, userID, factorID, passCode string) (*FactorVerifyResponse, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors/%v/verify", userID, factorID)
+
+	post := struct {
+		PassCode string `json:"passCode,omitempty"`
+	}{
+		passCode,
+	}
+
+	var verify FactorVerifyResponse
+	resp, err := s.client.call(ctx, "POST", u, post, &verify)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &verify, resp, nil
+}
+
+// PollTransaction polls a push verification transaction every interval until
+// the user answers: the returned FactorResult is then SUCCESS, REJECTED or TIMEOUT.
+func (s *FactorService) PollTransaction(ctx context.Context, userID, factorID, transactionID string, interval time.Duration) (*FactorVerifyResponse, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors/%v/transactions/%v", userID, factorID, transactionID)
+
+	for {
+		var verify FactorVerifyResponse
+		if _, err := s.client.call(ctx, "GET", u, nil, &verify); err != nil {
+			return nil, err
+		}
+
+		if verify.FactorResult != FactorResultWaiting {
+			return &verify, nil
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ListFactors returns the factors enrolled by a user which have the given
+// status, such as ACTIVE, or all of them when status is empty. Okta can't
+// filter factors by status, so they are filtered once listed.
+func (s *FactorService) ListFactors(ctx context.Context, userID, status string) ([]*Factor, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors", userID)
+
+	var factors []*Factor
+	resp, err := s.client.call(ctx, "GET", u, nil, &factors)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if status == "" {
+		return factors, resp, nil
+	}
+
+	var matching []*Factor
+	for _, f := range factors {
+		if f.Status == status {
+			matching = append(matching, f)
+		}
+	}
+
+	return matching, resp, nil
+}
+
+// EnrollFactor enrolls a factor for a user. Only the FactorType, Provider and
+// Profile of factor are sent; for a security question, see NewQuestionFactor.
+// SMS, call, email and TOTP factors are returned in PENDING_ACTIVATION status
+// until the code sent to the user is passed to ActivateFactor.
+// An unknown factor type, or one its provider doesn't offer, fails before
+// any request is sent.
+func (s *FactorService) EnrollFactor(ctx context.Context, userID string, factor *Factor) (*Factor, *Response, error) {
+	if err := validateFactor(factor.FactorType, factor.Provider); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("/api/v1/users/%v/factors", userID)
+
+	post := struct {
+		FactorType FactorType             `json:"factorType"`
+		Provider   FactorProvider         `json:"provider"`
+		Profile    map[string]interface{} `json:"profile,omitempty"`
+	}{
+		factor.FactorType,
+		factor.Provider,
+		factor.Profile,
+	}
+
+	var enrolled Factor
+	resp, err := s.client.call(ctx, "POST", u, post, &enrolled)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &enrolled, resp, nil
+}
+
+// ActivateFactor activates a factor in PENDING_ACTIVATION status with the
+// passCode received by the user, and returns the active factor. A wrong code
+// fails with a 403 *ErrorResponse; the user can then be asked again.
+func (s *FactorService) ActivateFactor(ctx context.Context, userID, factorID, passCode string) (*Factor, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors/%v/lifecycle/activate", userID, factorID)
+
+	post := struct {
+		PassCode string `json:"passCode"`
+	}{
+		passCode,
+	}
+
+	var factor Factor
+	resp, err := s.client.call(ctx, "POST", u, post, &factor)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &factor, resp, nil
+}
+
+// ResendEnrollment resends the code of a pending SMS, call or email factor
+// enrollment. factor is the pending factor, as returned by EnrollFactor;
+// Okta refuses it with an *ErrorResponse once the factor is active.
+func (s *FactorService) ResendEnrollment(ctx context.Context, userID string, factor *Factor) (*Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors/%v/resend", userID, factor.ID)
+
+	post := struct {
+		FactorType FactorType             `json:"factorType"`
+		Provider   FactorProvider         `json:"provider"`
+		Profile    map[string]interface{} `json:"profile,omitempty"`
+	}{
+		factor.FactorType,
+		factor.Provider,
+		factor.Profile,
+	}
+
+	return s.client.call(ctx, "POST", u, post, nil)
+}
+
+// ListSupportedSecurityQuestions returns the questions a user can choose from
+// when enrolling the security question factor.
+func (s *FactorService) ListSupportedSecurityQuestions(ctx context.Context, userID string) ([]*SecurityQuestion, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors/questions", userID)
+
+	var questions []*SecurityQuestion
+	resp, err := s.client.call(ctx, "GET", u, nil, &questions)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return questions, resp, nil
+}
+
+// ListSupported returns the factors a user can enroll, with their provider
+// and whether their enrollment is required, as enabled in the org.
+func (s *FactorService) ListSupported(ctx context.Context, userID string) ([]*Factor, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/factors/catalog", userID)
+
+	var factors []*Factor
+	resp, err := s.client.call(ctx, "GET", u, nil, &factors)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return factors, resp, nil
+}
@@ -0,0 +1,124 @@
+package okta
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// filterTimeFormat is the timestamp format Okta expects in filter expressions.
+const filterTimeFormat = oktaTimeFormat
+
+// quoteFilterValue returns s as a double-quoted filter string literal.
+func quoteFilterValue(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// quoteFilterTime returns t as a double-quoted filter timestamp literal,
+// ready to be compared with: it must not be quoted again.
+func quoteFilterTime(t time.Time) string {
+	return quoteFilterValue(t.UTC().Format(filterTimeFormat))
+}
+
+// LogFilter builds a filter expression for LogListOptions.Filter.
+// Conditions are joined with "and":
+//
+//	f := okta.NewLogFilter().EventType("user.session.start").Actor(userID)
+//	events, _, err := c.Log.List(ctx, &okta.LogListOptions{Filter: f.String()})
+type LogFilter struct {
+	filter
+}
+
+// NewLogFilter returns an empty LogFilter.
+func NewLogFilter() *LogFilter {
+	return &LogFilter{}
+}
+
+func (f *LogFilter) add(attr, op, value string) *LogFilter {
+	f.filter.add(attr, op, value)
+	return f
+}
+
+// EventType matches events of the given type, e.g. "user.session.start".
+func (f *LogFilter) EventType(eventType string) *LogFilter {
+	return f.add("eventType", "eq", quoteFilterValue(eventType))
+}
+
+// Actor matches events performed by the entity with the given ID.
+func (f *LogFilter) Actor(id string) *LogFilter {
+	return f.add("actor.id", "eq", quoteFilterValue(id))
+}
+
+// Target matches events affecting the entity with the given ID.
+func (f *LogFilter) Target(id string) *LogFilter {
+	return f.add("target.id", "eq", quoteFilterValue(id))
+}
+
+// Outcome matches events with the given outcome result, e.g. "FAILURE".
+func (f *LogFilter) Outcome(result string) *LogFilter {
+	return f.add("outcome.result", "eq", quoteFilterValue(result))
+}
+
+// PublishedAfter matches events published after t.
+func (f *LogFilter) PublishedAfter(t time.Time) *LogFilter {
+	return f.add("published", "gt", quoteFilterTime(t))
+}
+
+// PublishedBefore matches events published before t.
+func (f *LogFilter) PublishedBefore(t time.Time) *LogFilter {
+	return f.add("published", "lt", quoteFilterTime(t))
+}
+
+// filter holds the conditions of a filter expression.
+type filter struct {
+	conditions []string
+}
+
+func (f *filter) add(attr, op, value string) {
+	f.conditions = append(f.conditions, fmt.Sprintf("%s %s %s", attr, op, value))
+}
+
+// String returns the filter expression.
+func (f *filter) String() string {
+	return strings.Join(f.conditions, " and ")
+}
+
+// UserFilter builds a filter expression for UserListOptions.Filter.
+// Conditions are joined with "and":
+//
+//	f := okta.NewUserFilter().Status(okta.UserStatusActive).UpdatedAfter(lastSync)
+//	users, _, err := c.User.List(ctx, &okta.UserListOptions{Filter: f.String()})
+type UserFilter struct {
+	filter
+}
+
+// NewUserFilter returns an empty UserFilter.
+func NewUserFilter() *UserFilter {
+	return &UserFilter{}
+}
+
+func (f *UserFilter) add(attr, op, value string) *UserFilter {
+	f.filter.add(attr, op, value)
+	return f
+}
+
+// Status matches users with the given status, e.g. UserStatusActive.
+func (f *UserFilter) Status(status string) *UserFilter {
+	return f.add("status", "eq", quoteFilterValue(status))
+}
+
+// Login matches the user with the given login.
+func (f *UserFilter) Login(login string) *UserFilter {
+	return f.add("profile.login", "eq", quoteFilterValue(login))
+}
+
+// UpdatedAfter matches users updated after t, for incremental syncs.
+func (f *UserFilter) UpdatedAfter(t time.Time) *UserFilter {
+	return f.add("lastUpdated", "gt", quoteFilterTime(t))
+}
+
+// UpdatedBefore matches users updated before t.
+func (f *UserFilter) UpdatedBefore(t time.Time) *UserFilter {
+	return f.add("lastUpdated", "lt", quoteFilterTime(t))
+}
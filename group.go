@@ -1,60 +1,131 @@
 package okta
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
 )
 
+var (
+	// ErrGroupNotFound is returned by GetByName when no group has the name.
+	ErrGroupNotFound = errors.New("okta: group not found")
+
+	// ErrMultipleGroups is returned by GetByName when several groups have the name.
+	ErrMultipleGroups = errors.New("okta: several groups have this name")
+)
+
 // GroupService deals with Okta groups.
 type GroupService service
 
+// Group types.
+const (
+	GroupTypeOkta    = "OKTA_GROUP" // a group managed in Okta
+	GroupTypeApp     = "APP_GROUP"  // a group imported from an application, e.g. Active Directory
+	GroupTypeBuiltIn = "BUILT_IN"   // a group of Okta, such as Everyone
+)
+
 type Group struct {
-	ID   string
-	Name string
+	ID                    string
+	Name                  string
+	Type                  string // GroupTypeOkta, GroupTypeApp or GroupTypeBuiltIn
+	Created               time.Time
+	LastUpdated           time.Time
+	LastMembershipUpdated time.Time
+	Links                 map[string]Link
+
+	// Stats is only set when the group was listed with the "stats" expand.
+	Stats *GroupStats
+}
+
+// GroupStats counts what a group is assigned to.
+type GroupStats struct {
+	UsersCount             int `json:"usersCount"`
+	AppsCount              int `json:"appsCount"`
+	GroupPushMappingsCount int `json:"groupPushMappingsCount"`
 }
 
 type group struct {
 	ID                    string    `json:"id"`
-	Created               time.Time `json:"created"`
-	LastUpdated           time.Time `json:"lastUpdated"`
-	LastMembershipUpdated time.Time `json:"lastMembershipUpdated"`
+	Created               timestamp `json:"created"`
+	LastUpdated           timestamp `json:"lastUpdated"`
+	LastMembershipUpdated timestamp `json:"lastMembershipUpdated"`
 	ObjectClass           []string  `json:"objectClass"`
 	Type                  string    `json:"type"`
 	Profile               struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	} `json:"profile"`
-	Links struct {
-		Logo []struct {
-			Name string `json:"name"`
-			Href string `json:"href"`
-			Type string `json:"type"`
-		} `json:"logo"`
-		Users struct {
-			Href string `json:"href"`
-		} `json:"users"`
-		Apps struct {
-			Href string `json:"href"`
-		} `json:"apps"`
-	} `json:"_links"`
-}
-
-type getGroupsQuery struct {
-	Limit int `url:"limit,omitempty"`
-}
-
-// GetGroups returns all the Okta groups.
-func (s *GroupService) GetGroups(ctx context.Context) ([]*Group, error) {
-	u := "/api/v1/groups"
+	Links    map[string]Link `json:"_links"`
+	Embedded struct {
+		Stats *GroupStats `json:"stats"`
+	} `json:"_embedded"`
+}
 
-	uu, err := addOptions(u, &getGroupsQuery{Limit: 200})
-	if err != nil {
-		return nil, err
+func (g *group) toGroup() *Group {
+	return &Group{
+		ID:                    g.ID,
+		Name:                  g.Profile.Name,
+		Type:                  g.Type,
+		Created:               time.Time(g.Created),
+		LastUpdated:           time.Time(g.LastUpdated),
+		LastMembershipUpdated: time.Time(g.LastMembershipUpdated),
+		Links:                 g.Links,
+		Stats:                 g.Embedded.Stats,
 	}
+}
 
-	req, err := s.client.NewRequest("GET", uu, nil)
+// toGroups converts raw groups, of any type.
+func toGroups(rawGroups []*group) []*Group {
+	groups := make([]*Group, 0, len(rawGroups))
+	for _, g := range rawGroups {
+		groups = append(groups, g.toGroup())
+	}
+
+	return groups
+}
+
+// toOktaGroups converts raw groups, skipping anything that is not an
+// OKTA_GROUP, such as the groups imported from applications.
+func toOktaGroups(rawGroups []*group) []*Group {
+	var groups []*Group
+	for _, g := range rawGroups {
+		if g.Type != GroupTypeOkta {
+			continue
+		}
+
+		groups = append(groups, g.toGroup())
+	}
+
+	return groups
+}
+
+// GroupListOptions allows to filter the groups returned by List.
+type GroupListOptions struct {
+	// Q matches groups whose name starts with Q.
+	Q string `url:"q,omitempty"`
+
+	// Search is a search expression on the group properties,
+	// for example `profile.name eq "Engineering"`.
+	Search string `url:"search,omitempty"`
+
+	// Expand embeds related resources in each group, e.g. "stats" to set
+	// Group.Stats. Okta expects the values comma-separated in a single
+	// expand parameter.
+	Expand []string `url:"expand,omitempty,comma"`
+
+	ListOptions
+}
+
+// GetGroups returns all the Okta groups, of type GroupTypeOkta. See ListAll
+// for the groups of every type.
+func (s *GroupService) GetGroups(ctx context.Context) ([]*Group, error) {
+	u := "/api/v1/groups"
+
+	req, err := s.client.NewRequestWithQuery("GET", u, &ListOptions{Limit: 200}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,58 +140,197 @@ func (s *GroupService) GetGroups(ctx context.Context) ([]*Group, error) {
 		return nil, err
 	}
 
-	var groups []*Group
-	for _, g := range rawGroups {
-		// skipping anything that is not an OKTA_GROUP (not sure when this can happen).
-		if g.Type != "OKTA_GROUP" {
-			continue
+	return toOktaGroups(rawGroups), nil
+}
+
+// GetGroupMembership returns all users from a group. On error, including the
+// cancellation of ctx, the users fetched so far are returned along with it.
+// Okta returns the members as full users, profile included, so there is no
+// need to fetch each user again: it takes one call per page of 200 users.
+func (s *GroupService) GetGroupMembership(ctx context.Context, groupID string) ([]*User, error) {
+	u := fmt.Sprintf("/api/v1/groups/%v/users", groupID)
+
+	uu, err := addOptions(u, &ListOptions{Limit: 200})
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*User
+	for uu != "" {
+		var page []*User
+		resp, err := s.client.call(ctx, "GET", uu, nil, &page)
+		if err != nil {
+			return users, err
 		}
 
-		groups = append(groups, &Group{
-			ID:   g.ID,
-			Name: g.Profile.Name,
-		})
+		users = append(users, page...)
+		if uu, err = s.client.nextPage(resp); err != nil {
+			return users, err
+		}
 	}
 
-	return groups, nil
+	return users, nil
 }
 
-// GetGroupMembership returns all users from a group.
-func (s *GroupService) GetGroupMembership(ctx context.Context, groupID string) ([]*User, error) {
-	u := fmt.Sprintf("/api/v1/groups/%v/users", groupID)
+// EffectiveMembers returns the users who are effectively members of a group.
+// Okta groups can't contain other groups, so these are exactly the members
+// returned by GetGroupMembership; it exists so that code written for
+// directories with nested groups reads the same.
+func (s *GroupService) EffectiveMembers(ctx context.Context, groupID string) ([]*User, error) {
+	return s.GetGroupMembership(ctx, groupID)
+}
+
+// AddUser adds a user to a group. Adding a user who is already a member is a no-op.
+func (s *GroupService) AddUser(ctx context.Context, groupID, userID string) (*Response, error) {
+	u := fmt.Sprintf("/api/v1/groups/%v/users/%v", groupID, userID)
+
+	return s.client.call(ctx, "PUT", u, nil, nil)
+}
+
+// RemoveUser removes a user from a group.
+func (s *GroupService) RemoveUser(ctx context.Context, groupID, userID string) (*Response, error) {
+	u := fmt.Sprintf("/api/v1/groups/%v/users/%v", groupID, userID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// reconcileConcurrency is the number of membership changes ReconcileMembers applies at once.
+const reconcileConcurrency = 4
 
-	uu, err := addOptions(u, &getUsersQuery{Limit: 200})
+// ListAssignedRoles returns the admin roles assigned to a group, which its
+// members hold.
+func (s *GroupService) ListAssignedRoles(ctx context.Context, groupID string) ([]*Role, *Response, error) {
+	u := fmt.Sprintf("/api/v1/groups/%v/roles", groupID)
+
+	var roles []*Role
+	resp, err := s.client.call(ctx, "GET", u, nil, &roles)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	req, err := s.client.NewRequest("GET", uu, nil)
+	return roles, resp, nil
+}
+
+// AssignRole assigns an admin role to a group, granting it to its members.
+func (s *GroupService) AssignRole(ctx context.Context, groupID string, role *RoleAssignment) (*Role, *Response, error) {
+	u := fmt.Sprintf("/api/v1/groups/%v/roles", groupID)
+
+	var assigned Role
+	resp, err := s.client.call(ctx, "POST", u, role, &assigned)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
-	if err := s.client.AddAuthorization(ctx, req); err != nil {
-		return nil, err
+	return &assigned, resp, nil
+}
+
+// RemoveRole unassigns the admin role roleID, as returned by
+// ListAssignedRoles, from a group.
+func (s *GroupService) RemoveRole(ctx context.Context, groupID, roleID string) (*Response, error) {
+	u := fmt.Sprintf("/api/v1/groups/%v/roles/%v", groupID, roleID)
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// PlanMembers computes the changes ReconcileMembers would make to the members
+// of a group, without applying them: the IDs of the desired users who aren't
+// members, and of the members who aren't desired, both sorted.
+func (s *GroupService) PlanMembers(ctx context.Context, groupID string, desiredUserIDs []string) (toAdd, toRemove []string, err error) {
+	members, err := s.GetGroupMembership(ctx, groupID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var users []*User
-	_, err = s.client.Do(ctx, req, &users)
+	current := make(map[string]bool, len(members))
+	for _, m := range members {
+		current[m.ID] = true
+	}
+
+	desired := make(map[string]bool, len(desiredUserIDs))
+	for _, id := range desiredUserIDs {
+		if desired[id] {
+			continue
+		}
+
+		desired[id] = true
+		if !current[id] {
+			toAdd = append(toAdd, id)
+		}
+	}
+	for _, m := range members {
+		if !desired[m.ID] {
+			toRemove = append(toRemove, m.ID)
+		}
+	}
+
+	sort.Strings(toAdd)
+	sort.Strings(toRemove)
+	return toAdd, toRemove, nil
+}
+
+// ReconcileMembers makes desiredUserIDs the members of a group: it adds the
+// missing users and removes the members who aren't desired, returning the IDs
+// of the users actually added and removed. See PlanMembers for a dry run.
+// Changes are applied concurrently; rate limited calls are retried as
+// configured by Client.MaxRetries. On error, the changes that succeeded are
+// still returned along with the first error.
+func (s *GroupService) ReconcileMembers(ctx context.Context, groupID string, desiredUserIDs []string) (added, removed []string, err error) {
+	toAdd, toRemove, err := s.PlanMembers(ctx, groupID, desiredUserIDs)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return users, nil
+	type change struct {
+		userID string
+		remove bool
+	}
+
+	var changes []change
+	for _, id := range toAdd {
+		changes = append(changes, change{userID: id})
+	}
+	for _, id := range toRemove {
+		changes = append(changes, change{userID: id, remove: true})
+	}
+
+	var mu sync.Mutex
+	forEach(ctx, len(changes), reconcileConcurrency, func(i int) {
+		c := changes[i]
+
+		var cerr error
+		if c.remove {
+			_, cerr = s.RemoveUser(ctx, groupID, c.userID)
+		} else {
+			_, cerr = s.AddUser(ctx, groupID, c.userID)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case cerr != nil:
+			if err == nil {
+				err = cerr
+			}
+		case c.remove:
+			removed = append(removed, c.userID)
+		default:
+			added = append(added, c.userID)
+		}
+	})
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed, err
 }
 
+// GetUserGroups returns the groups of type GroupTypeOkta a user is a member of.
 func (s *GroupService) GetUserGroups(ctx context.Context, userID string) ([]*Group, error) {
 	u := fmt.Sprintf("/api/v1/users/%v/groups", userID)
 
-	uu, err := addOptions(u, &getGroupsQuery{Limit: 200})
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := s.client.NewRequest("GET", uu, nil)
+	req, err := s.client.NewRequestWithQuery("GET", u, &ListOptions{Limit: 200}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -135,18 +345,94 @@ func (s *GroupService) GetUserGroups(ctx context.Context, userID string) ([]*Gro
 		return nil, err
 	}
 
+	return toOktaGroups(rawGroups), nil
+}
+
+// List returns a page of the groups matching opt, of every type unless
+// opt.Search filters on it, e.g. `type eq "OKTA_GROUP"`. To list the next
+// page, set opt.After to the NextCursor of the returned *Response, until it
+// is empty.
+func (s *GroupService) List(ctx context.Context, opt *GroupListOptions) ([]*Group, *Response, error) {
+	u, err := addOptions("/api/v1/groups", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rawGroups []*group
+	resp, err := s.client.call(ctx, "GET", u, nil, &rawGroups)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return toGroups(rawGroups), resp, nil
+}
+
+// Pages calls fn with each page of the groups matching opt, following the
+// NextCursor of the responses, from opt.After if set. opt isn't modified.
+// It stops at the first error of fn, which it returns, or when ctx is done.
+func (s *GroupService) Pages(ctx context.Context, opt *GroupListOptions, fn func(page []*Group) error) error {
+	var o GroupListOptions
+	if opt != nil {
+		o = *opt
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, resp, err := s.List(ctx, &o)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if o.After = resp.NextCursor; o.After == "" {
+			return nil
+		}
+	}
+}
+
+// ListAll returns all the groups matching opt, listing them page after page
+// with Pages. On error, including the cancellation of ctx, the groups listed
+// so far are returned along with it.
+func (s *GroupService) ListAll(ctx context.Context, opt *GroupListOptions) ([]*Group, error) {
 	var groups []*Group
-	for _, g := range rawGroups {
-		// skipping anything that is not an OKTA_GROUP (not sure when this can happen).
-		if g.Type != "OKTA_GROUP" {
+	err := s.Pages(ctx, opt, func(page []*Group) error {
+		groups = append(groups, page...)
+		return nil
+	})
+
+	return groups, err
+}
+
+// GetByName returns the group with the given name. It returns ErrGroupNotFound
+// if there's no such group, and ErrMultipleGroups if the name isn't unique.
+func (s *GroupService) GetByName(ctx context.Context, name string) (*Group, *Response, error) {
+	groups, resp, err := s.List(ctx, &GroupListOptions{
+		Search: "profile.name eq " + quoteFilterValue(name),
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var match *Group
+	for _, g := range groups {
+		if g.Name != name {
 			continue
 		}
 
-		groups = append(groups, &Group{
-			ID:   g.ID,
-			Name: g.Profile.Name,
-		})
+		if match != nil {
+			return nil, resp, ErrMultipleGroups
+		}
+		match = g
+	}
+
+	if match == nil {
+		return nil, resp, ErrGroupNotFound
 	}
 
-	return groups, nil
+	return match, resp, nil
 }
@@ -0,0 +1,75 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestGroupListOptionsExpandIsCommaSeparated(t *testing.T) {
+	u, err := addOptions("/api/v1/groups", &GroupListOptions{Expand: []string{"stats", "app"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Query()["expand"]; len(got) != 1 || got[0] != "stats,app" {
+		t.Errorf("expand = %q, want a single stats,app value", got)
+	}
+}
+
+// No Okta parameter of the options is repeated yet: this checks that a slice
+// without the comma option would be, for the endpoints expecting it.
+func TestAddOptionsRepeatsSliceWithoutComma(t *testing.T) {
+	opt := struct {
+		Type []string `url:"type,omitempty"`
+	}{[]string{"a", "b"}}
+
+	u, err := addOptions("/api/v1/resources", &opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/api/v1/resources?type=a&type=b"; u != want {
+		t.Errorf("addOptions = %q, want %q", u, want)
+	}
+}
+
+func TestListAllGroupTypes(t *testing.T) {
+	c, mux := setup(t)
+
+	mux.HandleFunc("/api/v1/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id": "00g1", "type": "OKTA_GROUP", "profile": {"name": "Engineering"}},
+			{"id": "00g2", "type": "APP_GROUP", "profile": {"name": "Domain Users"}},
+			{"id": "00g3", "type": "BUILT_IN", "profile": {"name": "Everyone"}}
+		]`))
+	})
+
+	groups, err := c.Group.ListAll(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{GroupTypeOkta, GroupTypeApp, GroupTypeBuiltIn}
+	if len(groups) != len(want) {
+		t.Fatalf("listed %d groups, want %d", len(groups), len(want))
+	}
+	for i, g := range groups {
+		if g.Type != want[i] {
+			t.Errorf("group %v has type %q, want %q", g.ID, g.Type, want[i])
+		}
+	}
+
+	g, _, err := c.Group.GetByName(context.Background(), "Domain Users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.ID != "00g2" {
+		t.Errorf("GetByName = %v, want 00g2", g.ID)
+	}
+}
@@ -0,0 +1,29 @@
+package okta
+
+// Header names used by Okta, in API responses as well as in the requests Okta
+// sends to event and inline hooks.
+const (
+	// HeaderAuthorization carries the API token of API requests, and the
+	// secret configured on a hook in the requests Okta sends to it.
+	HeaderAuthorization = "Authorization"
+
+	// HeaderRequestID identifies a request to Okta support.
+	HeaderRequestID = "X-Okta-Request-Id"
+
+	// HeaderVerificationChallenge carries the one-time challenge of the
+	// verification request Okta sends when an event hook is registered.
+	// The hook must echo it back as {"verification": "<challenge>"}.
+	HeaderVerificationChallenge = "X-Okta-Verification-Challenge"
+
+	// HeaderRateLimitLimit, HeaderRateLimitRemaining and HeaderRateLimitReset
+	// describe the rate limit of the endpoint a response comes from.
+	HeaderRateLimitLimit     = "X-Rate-Limit-Limit"
+	HeaderRateLimitRemaining = "X-Rate-Limit-Remaining"
+	HeaderRateLimitReset     = "X-Rate-Limit-Reset"
+
+	// HeaderWarning, HeaderDeprecation and HeaderOktaDeprecated announce, on
+	// a response, that the endpoint or one of its parameters is deprecated.
+	HeaderWarning        = "Warning"
+	HeaderDeprecation    = "Deprecation"
+	HeaderOktaDeprecated = "Okta-Deprecated"
+)
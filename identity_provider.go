@@ -0,0 +1,324 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// IdentityProviderService manages the external identity providers users can
+// sign in with, such as SAML or social providers.
+type IdentityProviderService service
+
+// Identity provider types.
+const (
+	IdPTypeSAML2     = "SAML2"
+	IdPTypeOIDC      = "OIDC"
+	IdPTypeGoogle    = "GOOGLE"
+	IdPTypeFacebook  = "FACEBOOK"
+	IdPTypeMicrosoft = "MICROSOFT"
+	IdPTypeLinkedIn  = "LINKEDIN"
+	IdPTypeApple     = "APPLE"
+)
+
+// IdentityProvider is an external identity provider. The keys of Protocol,
+// such as the endpoints and credentials, depend on Type; Policy holds how
+// users are provisioned and matched with existing users.
+type IdentityProvider struct {
+	ID          string                 `json:"id,omitempty"`
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name"`
+	Status      string                 `json:"status,omitempty"`
+	IssuerMode  string                 `json:"issuerMode,omitempty"` // ORG_URL or CUSTOM_URL
+	Protocol    map[string]interface{} `json:"protocol,omitempty"`
+	Policy      map[string]interface{} `json:"policy,omitempty"`
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes an identity provider, mapping null or empty timestamps
+// to the zero time.
+func (i *IdentityProvider) UnmarshalJSON(data []byte) error {
+	type alias IdentityProvider
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(i)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	i.Created = time.Time(aux.Created)
+	i.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// MarshalJSON encodes an identity provider without its read-only timestamps,
+// which would otherwise be sent as the year 1 until they are set.
+func (i IdentityProvider) MarshalJSON() ([]byte, error) {
+	type alias IdentityProvider
+	return json.Marshal(&struct {
+		*alias
+		Created     *struct{} `json:"created,omitempty"`
+		LastUpdated *struct{} `json:"lastUpdated,omitempty"`
+	}{alias: (*alias)(&i)})
+}
+
+// IdPUser is the link of a user to an identity provider: ID is the ID of the
+// Okta user, and its idp link references the identity provider.
+type IdPUser struct {
+	ID          string                 `json:"id"`
+	ExternalID  string                 `json:"externalId"` // ID of the user in the identity provider
+	Profile     map[string]interface{} `json:"profile"`
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links"`
+}
+
+// UnmarshalJSON decodes an identity provider user, mapping null or empty
+// timestamps to the zero time.
+func (i *IdPUser) UnmarshalJSON(data []byte) error {
+	type alias IdPUser
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(i)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	i.Created = time.Time(aux.Created)
+	i.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// IdPListOptions allows to filter the identity providers returned by List.
+type IdPListOptions struct {
+	Q    string `url:"q,omitempty"` // matches the start of the name
+	Type string `url:"type,omitempty"`
+
+	ListOptions
+}
+
+func idpURL(id string) string {
+	return fmt.Sprintf("/api/v1/idps/%v", id)
+}
+
+// List returns a page of the identity providers matching opt.
+func (s *IdentityProviderService) List(ctx context.Context, opt *IdPListOptions) ([]*IdentityProvider, *Response, error) {
+	u, err := addOptions("/api/v1/idps", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var idps []*IdentityProvider
+	resp, err := s.client.call(ctx, "GET", u, nil, &idps)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return idps, resp, nil
+}
+
+// Get returns an identity provider.
+func (s *IdentityProviderService) Get(ctx context.Context, id string) (*IdentityProvider, *Response, error) {
+	var idp IdentityProvider
+	resp, err := s.client.call(ctx, "GET", idpURL(id), nil, &idp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &idp, resp, nil
+}
+
+// Create creates an identity provider.
+func (s *IdentityProviderService) Create(ctx context.Context, idp *IdentityProvider) (*IdentityProvider, *Response, error) {
+	var created IdentityProvider
+	resp, err := s.client.call(ctx, "POST", "/api/v1/idps", idp, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update replaces an identity provider.
+func (s *IdentityProviderService) Update(ctx context.Context, id string, idp *IdentityProvider) (*IdentityProvider, *Response, error) {
+	var updated IdentityProvider
+	resp, err := s.client.call(ctx, "PUT", idpURL(id), idp, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Delete deletes an identity provider, and unlinks its users.
+func (s *IdentityProviderService) Delete(ctx context.Context, id string) (*Response, error) {
+	return s.client.call(ctx, "DELETE", idpURL(id), nil, nil)
+}
+
+// Activate activates an identity provider.
+func (s *IdentityProviderService) Activate(ctx context.Context, id string) (*IdentityProvider, *Response, error) {
+	return s.lifecycle(ctx, id, "activate")
+}
+
+// Deactivate deactivates an identity provider.
+func (s *IdentityProviderService) Deactivate(ctx context.Context, id string) (*IdentityProvider, *Response, error) {
+	return s.lifecycle(ctx, id, "deactivate")
+}
+
+func (s *IdentityProviderService) lifecycle(ctx context.Context, id, action string) (*IdentityProvider, *Response, error) {
+	var idp IdentityProvider
+	resp, err := s.client.lifecycle(ctx, idpURL(id), action, nil, &idp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &idp, resp, nil
+}
+
+// ListUsers returns the users linked to an identity provider, see LinkUser.
+func (s *IdentityProviderService) ListUsers(ctx context.Context, id string) ([]*IdPUser, *Response, error) {
+	var users []*IdPUser
+	resp, err := s.client.call(ctx, "GET", idpURL(id)+"/users", nil, &users)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return users, resp, nil
+}
+
+// GetLinkedUser returns the link of a user to an identity provider.
+func (s *IdentityProviderService) GetLinkedUser(ctx context.Context, idpID, userID string) (*IdPUser, *Response, error) {
+	u := fmt.Sprintf("%v/users/%v", idpURL(idpID), userID)
+
+	var user IdPUser
+	resp, err := s.client.call(ctx, "GET", u, nil, &user)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &user, resp, nil
+}
+
+// LinkUser links an Okta user to the account externalID of an identity
+// provider, so that signing in with that account signs in as the user.
+func (s *IdentityProviderService) LinkUser(ctx context.Context, idpID, userID, externalID string) (*IdPUser, *Response, error) {
+	u := fmt.Sprintf("%v/users/%v", idpURL(idpID), userID)
+
+	post := struct {
+		ExternalID string `json:"externalId"`
+	}{
+		externalID,
+	}
+
+	var user IdPUser
+	resp, err := s.client.call(ctx, "POST", u, post, &user)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &user, resp, nil
+}
+
+// UnlinkUser removes the link of a user to an identity provider.
+func (s *IdentityProviderService) UnlinkUser(ctx context.Context, idpID, userID string) (*Response, error) {
+	u := fmt.Sprintf("%v/users/%v", idpURL(idpID), userID)
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// ListUserIdPs returns the identity providers a user is linked to.
+func (s *IdentityProviderService) ListUserIdPs(ctx context.Context, userID string) ([]*IdentityProvider, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/idps", userID)
+
+	var idps []*IdentityProvider
+	resp, err := s.client.call(ctx, "GET", u, nil, &idps)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return idps, resp, nil
+}
+
+// ListSigningKeys returns the signing keys of an identity provider.
+func (s *IdentityProviderService) ListSigningKeys(ctx context.Context, idpID string) ([]*JSONWebKey, *Response, error) {
+	var keys []*JSONWebKey
+	resp, err := s.client.call(ctx, "GET", idpURL(idpID)+"/credentials/keys", nil, &keys)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return keys, resp, nil
+}
+
+// ListCSRs returns the certificate signing requests of an identity provider.
+func (s *IdentityProviderService) ListCSRs(ctx context.Context, idpID string) ([]*CSR, *Response, error) {
+	var csrs []*CSR
+	resp, err := s.client.call(ctx, "GET", idpURL(idpID)+"/credentials/csrs", nil, &csrs)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return csrs, resp, nil
+}
+
+// GenerateCSR generates a key pair for an identity provider, and returns the
+// certificate signing request of its public key, see PublishCSR.
+func (s *IdentityProviderService) GenerateCSR(ctx context.Context, idpID string, metadata *CSRMetadata) (*CSR, *Response, error) {
+	var csr CSR
+	resp, err := s.client.call(ctx, "POST", idpURL(idpID)+"/credentials/csrs", metadata, &csr)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &csr, resp, nil
+}
+
+// GetCSR returns a certificate signing request of an identity provider.
+func (s *IdentityProviderService) GetCSR(ctx context.Context, idpID, csrID string) (*CSR, *Response, error) {
+	u := fmt.Sprintf("%v/credentials/csrs/%v", idpURL(idpID), csrID)
+
+	var csr CSR
+	resp, err := s.client.call(ctx, "GET", u, nil, &csr)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &csr, resp, nil
+}
+
+// PublishCSR publishes the certificate signed for a certificate signing
+// request, PEM or DER encoded, and returns the resulting signing key of the
+// identity provider. The CSR is deleted once published.
+func (s *IdentityProviderService) PublishCSR(ctx context.Context, idpID, csrID string, certificate []byte) (*JSONWebKey, *Response, error) {
+	u := fmt.Sprintf("%v/credentials/csrs/%v/lifecycle/publish", idpURL(idpID), csrID)
+
+	mediaType := "application/pkix-cert"
+	if bytes.HasPrefix(bytes.TrimSpace(certificate), []byte("-----BEGIN")) {
+		mediaType = "application/x-pem-file"
+	}
+	ctx = WithRequestOptions(ctx, WithContentType(mediaType))
+
+	var key JSONWebKey
+	resp, err := s.client.call(ctx, "POST", u, bytes.NewReader(certificate), &key)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &key, resp, nil
+}
+
+// DeleteCSR deletes a certificate signing request of an identity provider,
+// along with its key pair.
+func (s *IdentityProviderService) DeleteCSR(ctx context.Context, idpID, csrID string) (*Response, error) {
+	u := fmt.Sprintf("%v/credentials/csrs/%v", idpURL(idpID), csrID)
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
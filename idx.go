@@ -0,0 +1,219 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// IDXService drives the Interaction Code flow of Okta Identity Engine orgs,
+// which replaces the authentication API. A flow starts with Interact, then
+// each IDXResponse lists the remediations the user can take next, until one
+// of them returns SuccessWithInteractionCode.
+//
+// Its requests are unauthenticated: they are made on behalf of the user.
+type IDXService service
+
+// mediaTypeION is the media type of the IDX API, pinned to its version.
+const mediaTypeION = "application/ion+json; okta-version=1.0.0"
+
+// IDXInteractRequest starts an interaction for an OAuth client, see Interact.
+type IDXInteractRequest struct {
+	ClientID            string
+	Scopes              []string
+	RedirectURI         string
+	CodeChallenge       string
+	CodeChallengeMethod string // S256
+	State               string
+}
+
+// IDXResponse is the state of an interaction.
+type IDXResponse struct {
+	Version     string          `json:"version"`
+	StateHandle string          `json:"stateHandle"`
+	ExpiresAt   time.Time       `json:"expiresAt"`
+	Intent      string          `json:"intent"`
+	Remediation *IDXRemediation `json:"remediation"`
+	Messages    *IDXMessages    `json:"messages"`
+
+	// SuccessWithInteractionCode is set once the user is authenticated:
+	// its form holds the interaction code to exchange for tokens.
+	SuccessWithInteractionCode *IDXRemediationOption `json:"successWithInteractionCode"`
+}
+
+// UnmarshalJSON decodes an interaction state, mapping null or empty timestamps
+// to the zero time.
+func (i *IDXResponse) UnmarshalJSON(data []byte) error {
+	type alias IDXResponse
+	aux := &struct {
+		*alias
+		ExpiresAt timestamp `json:"expiresAt"`
+	}{alias: (*alias)(i)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	i.ExpiresAt = time.Time(aux.ExpiresAt)
+	return nil
+}
+
+// IDXRemediation lists the options the user can take next.
+type IDXRemediation struct {
+	Type  string                  `json:"type"`
+	Value []*IDXRemediationOption `json:"value"`
+}
+
+// IDXRemediationOption is a form to submit to Href, see IDXService.Proceed.
+type IDXRemediationOption struct {
+	Rel     []string    `json:"rel"`
+	Name    string      `json:"name"`
+	Href    string      `json:"href"`
+	Method  string      `json:"method"`
+	Accepts string      `json:"accepts"`
+	Value   []*IDXField `json:"value"`
+}
+
+// IDXField is a field of a remediation form. Form is set for nested objects,
+// and Options for the fields with a choice of values.
+type IDXField struct {
+	Name     string      `json:"name"`
+	Label    string      `json:"label"`
+	Type     string      `json:"type"`
+	Value    interface{} `json:"value"`
+	Required bool        `json:"required"`
+	Visible  *bool       `json:"visible"`
+	Mutable  *bool       `json:"mutable"`
+	Secret   bool        `json:"secret"`
+	Form     *struct {
+		Value []*IDXField `json:"value"`
+	} `json:"form"`
+	Options []*struct {
+		Label string      `json:"label"`
+		Value interface{} `json:"value"`
+	} `json:"options"`
+}
+
+// IDXMessages are the messages to show to the user, such as a wrong password.
+type IDXMessages struct {
+	Type  string `json:"type"`
+	Value []*struct {
+		Message string `json:"message"`
+		Class   string `json:"class"` // ERROR or INFO
+		I18n    struct {
+			Key string `json:"key"`
+		} `json:"i18n"`
+	} `json:"value"`
+}
+
+// FindRemediation returns the remediation option of the given name, such as
+// "identify" or "challenge-authenticator", or nil if it isn't offered.
+func (r *IDXResponse) FindRemediation(name string) *IDXRemediationOption {
+	if r.Remediation == nil {
+		return nil
+	}
+
+	for _, o := range r.Remediation.Value {
+		if o.Name == name {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// Interact starts an interaction with the authorization server authServerID,
+// or with the org authorization server when empty, and returns its
+// interaction handle, to pass to Introspect.
+func (s *IDXService) Interact(ctx context.Context, authServerID string, interact *IDXInteractRequest) (string, *Response, error) {
+	u := "/oauth2/v1/interact"
+	if authServerID != "" {
+		u = "/oauth2/" + authServerID + "/v1/interact"
+	}
+
+	form := url.Values{
+		"client_id":             {interact.ClientID},
+		"scope":                 {strings.Join(interact.Scopes, " ")},
+		"redirect_uri":          {interact.RedirectURI},
+		"code_challenge":        {interact.CodeChallenge},
+		"code_challenge_method": {interact.CodeChallengeMethod},
+		"state":                 {interact.State},
+	}
+
+	req, err := s.client.NewRequest("POST", u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", nil, err
+	}
+
+	ctx = WithRequestOptions(ctx, WithContentType("application/x-www-form-urlencoded"))
+
+	var interaction struct {
+		InteractionHandle string `json:"interaction_handle"`
+	}
+	resp, err := s.client.Do(ctx, req, &interaction)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return interaction.InteractionHandle, resp, nil
+}
+
+// Introspect returns the current state of the interaction.
+func (s *IDXService) Introspect(ctx context.Context, interactionHandle string) (*IDXResponse, *Response, error) {
+	post := struct {
+		InteractionHandle string `json:"interactionHandle"`
+	}{
+		interactionHandle,
+	}
+
+	return s.idx(ctx, "/idp/idx/introspect", post)
+}
+
+// Challenge asks for a challenge of an authenticator of the user, such as an
+// email sent with a code, which is then answered through the
+// "challenge-authenticator" remediation.
+func (s *IDXService) Challenge(ctx context.Context, stateHandle, authenticatorID string) (*IDXResponse, *Response, error) {
+	post := struct {
+		StateHandle   string `json:"stateHandle"`
+		Authenticator struct {
+			ID string `json:"id"`
+		} `json:"authenticator"`
+	}{
+		StateHandle: stateHandle,
+	}
+	post.Authenticator.ID = authenticatorID
+
+	return s.idx(ctx, "/idp/idx/challenge", post)
+}
+
+// Proceed submits the form of a remediation option with values, which are
+// the values of its fields by name. The state handle is added to values.
+func (s *IDXService) Proceed(ctx context.Context, stateHandle string, option *IDXRemediationOption, values map[string]interface{}) (*IDXResponse, *Response, error) {
+	post := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		post[k] = v
+	}
+	post["stateHandle"] = stateHandle
+
+	return s.idx(ctx, option.Href, post)
+}
+
+// idx sends an unauthenticated request to the IDX API.
+func (s *IDXService) idx(ctx context.Context, u string, body interface{}) (*IDXResponse, *Response, error) {
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx = WithRequestOptions(ctx, WithContentType(mediaTypeION), WithAccept(mediaTypeION))
+
+	var state IDXResponse
+	resp, err := s.client.Do(ctx, req, &state)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &state, resp, nil
+}
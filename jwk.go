@@ -0,0 +1,87 @@
+package okta
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSONWebKey is a public key, along with its X.509 certificate chain
+// for signing keys. See RFC 7517.
+type JSONWebKey struct {
+	Kid         string          `json:"kid"`
+	Kty         string          `json:"kty"`
+	Use         string          `json:"use"`
+	Alg         string          `json:"alg,omitempty"`
+	E           string          `json:"e,omitempty"`
+	N           string          `json:"n,omitempty"`
+	X5c         []string        `json:"x5c,omitempty"`
+	X5tS256     string          `json:"x5t#S256,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	Created     time.Time       `json:"created"`
+	LastUpdated time.Time       `json:"lastUpdated"`
+	ExpiresAt   time.Time       `json:"expiresAt"`
+	Links       map[string]Link `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes a key, mapping null or empty timestamps to the zero
+// time.
+func (j *JSONWebKey) UnmarshalJSON(data []byte) error {
+	type alias JSONWebKey
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+		ExpiresAt   timestamp `json:"expiresAt"`
+	}{alias: (*alias)(j)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	j.Created = time.Time(aux.Created)
+	j.LastUpdated = time.Time(aux.LastUpdated)
+	j.ExpiresAt = time.Time(aux.ExpiresAt)
+	return nil
+}
+
+// CSR is a certificate signing request generated by Okta for a key pair it
+// holds, to get its certificate signed by a certificate authority.
+type CSR struct {
+	ID      string          `json:"id"`
+	CSR     string          `json:"csr"` // base64 DER
+	Kty     string          `json:"kty"`
+	Created time.Time       `json:"created"`
+	Links   map[string]Link `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes a CSR, mapping null or empty timestamps to the zero
+// time.
+func (c *CSR) UnmarshalJSON(data []byte) error {
+	type alias CSR
+	aux := &struct {
+		*alias
+		Created timestamp `json:"created"`
+	}{alias: (*alias)(c)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	c.Created = time.Time(aux.Created)
+	return nil
+}
+
+// CSRMetadata is the subject of a CSR to generate.
+type CSRMetadata struct {
+	Subject struct {
+		CountryName            string `json:"countryName,omitempty"`
+		StateOrProvinceName    string `json:"stateOrProvinceName,omitempty"`
+		LocalityName           string `json:"localityName,omitempty"`
+		OrganizationName       string `json:"organizationName,omitempty"`
+		OrganizationalUnitName string `json:"organizationalUnitName,omitempty"`
+		CommonName             string `json:"commonName,omitempty"`
+	} `json:"subject"`
+	SubjectAltNames struct {
+		DNSNames []string `json:"dnsNames,omitempty"`
+	} `json:"subjectAltNames"`
+}
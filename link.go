@@ -0,0 +1,36 @@
+package okta
+
+import "encoding/json"
+
+// Link is an entry of the _links object of an Okta resource. The links of a
+// resource reflect the operations currently allowed on it, e.g. a user only has
+// an "activate" link while it can be activated.
+type Link struct {
+	Name   string `json:"name,omitempty"`
+	Href   string `json:"href"`
+	Type   string `json:"type,omitempty"`
+	Method string `json:"method,omitempty"`
+	Hints  struct {
+		Allow []string `json:"allow,omitempty"`
+	} `json:"hints"`
+}
+
+// UnmarshalJSON decodes a link. A few links, such as the logo of groups and
+// applications, are lists of links: only the first one is kept.
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type link Link
+	if len(data) > 0 && data[0] == '[' {
+		var links []link
+		if err := json.Unmarshal(data, &links); err != nil {
+			return err
+		}
+
+		*l = Link{}
+		if len(links) > 0 {
+			*l = Link(links[0])
+		}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*link)(l))
+}
@@ -0,0 +1,356 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// LogService reads the Okta System Log.
+type LogService service
+
+// LogEvent is an entry of the System Log.
+type LogEvent struct {
+	UUID            string          `json:"uuid"`
+	Published       time.Time       `json:"published"`
+	EventType       string          `json:"eventType"`
+	Version         string          `json:"version"`
+	Severity        string          `json:"severity"`
+	LegacyEventType string          `json:"legacyEventType"`
+	DisplayMessage  string          `json:"displayMessage"`
+	Actor           *LogActor       `json:"actor"`
+	Client          *LogClient      `json:"client"`
+	Outcome         *LogOutcome     `json:"outcome"`
+	Target          []*LogTarget    `json:"target"`
+	Transaction     *LogTransaction `json:"transaction"`
+	DebugContext    struct {
+		// DebugData holds the details of the event, its numbers decoded
+		// as json.Number, since some are large integers.
+		DebugData map[string]interface{} `json:"debugData"`
+	} `json:"debugContext"`
+}
+
+// UnmarshalJSON decodes an event, its generic numbers as json.Number, and
+// mapping a null or empty timestamp to the zero time.
+func (e *LogEvent) UnmarshalJSON(data []byte) error {
+	type alias LogEvent
+	aux := &struct {
+		*alias
+		Published timestamp `json:"published"`
+	}{alias: (*alias)(e)}
+
+	if err := unmarshalNumbers(data, aux); err != nil {
+		return err
+	}
+
+	e.Published = time.Time(aux.Published)
+	return nil
+}
+
+// LogActor describes the entity that performed the action of a LogEvent.
+type LogActor struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	AlternateID string `json:"alternateId"`
+	DisplayName string `json:"displayName"`
+
+	// User is the actor, when it is a user, as resolved by ListWithActors.
+	User *User `json:"-"`
+}
+
+// LogTarget describes an entity affected by a LogEvent.
+type LogTarget struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	AlternateID string `json:"alternateId"`
+	DisplayName string `json:"displayName"`
+}
+
+// LogClient describes the client that issued the request of a LogEvent.
+type LogClient struct {
+	UserAgent struct {
+		RawUserAgent string `json:"rawUserAgent"`
+		OS           string `json:"os"`
+		Browser      string `json:"browser"`
+	} `json:"userAgent"`
+	Zone      string `json:"zone"`
+	Device    string `json:"device"`
+	ID        string `json:"id"`
+	IPAddress string `json:"ipAddress"`
+}
+
+// LogOutcome is the result of the action of a LogEvent.
+type LogOutcome struct {
+	Result string `json:"result"`
+	Reason string `json:"reason"`
+}
+
+// LogTransaction identifies the request that produced a LogEvent.
+type LogTransaction struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// LogListOptions allows to filter the System Log.
+type LogListOptions struct {
+	Since  OktaTime `url:"since,omitempty"`
+	Until  OktaTime `url:"until,omitempty"`
+	Filter string   `url:"filter,omitempty"` // see LogFilter
+	Q      string   `url:"q,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of System Log events matching opt.
+func (s *LogService) List(ctx context.Context, opt *LogListOptions) ([]*LogEvent, *Response, error) {
+	u, err := addOptions("/api/v1/logs", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []*LogEvent
+	resp, err := s.client.call(ctx, "GET", u, nil, &events)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return events, resp, nil
+}
+
+// ListFrom returns the page of System Log events matching opt which starts at
+// cursor, along with the cursor of the next page. An empty cursor starts from
+// the beginning of opt. Persisting nextCursor allows to resume a sync exactly
+// where it stopped. The returned cursor is "" when there is no next page,
+// which only happens when opt has an Until bound.
+func (s *LogService) ListFrom(ctx context.Context, cursor string, opt *LogListOptions) (events []*LogEvent, nextCursor string, resp *Response, err error) {
+	var o LogListOptions
+	if opt != nil {
+		o = *opt
+	}
+	o.After = cursor
+
+	events, resp, err = s.List(ctx, &o)
+	if err != nil {
+		return nil, "", resp, err
+	}
+
+	return events, cursorOf(resp), resp, nil
+}
+
+// actorConcurrency is the number of users ListWithActors fetches at once.
+const actorConcurrency = 4
+
+// ListWithActors behaves like List, and also resolves the actors which are users,
+// setting LogActor.User. Each user is fetched once per call, concurrently.
+// Actors whose user has since been deleted are left unresolved.
+func (s *LogService) ListWithActors(ctx context.Context, opt *LogListOptions) ([]*LogEvent, *Response, error) {
+	events, resp, err := s.List(ctx, opt)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if e.Actor == nil || e.Actor.Type != "User" || seen[e.Actor.ID] {
+			continue
+		}
+
+		seen[e.Actor.ID] = true
+		ids = append(ids, e.Actor.ID)
+	}
+
+	var mu sync.Mutex
+	users := make(map[string]*User, len(ids))
+	forEach(ctx, len(ids), actorConcurrency, func(i int) {
+		user, uerr := s.client.User.GetUser(ctx, ids[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case isNotFound(uerr):
+		case uerr != nil:
+			if err == nil {
+				err = uerr
+			}
+		default:
+			users[ids[i]] = user
+		}
+	})
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for _, e := range events {
+		if e.Actor != nil {
+			e.Actor.User = users[e.Actor.ID]
+		}
+	}
+
+	return events, resp, nil
+}
+
+// Export writes every System Log event matching opt to w as newline-delimited
+// JSON, one page at a time, keeping the events exactly as returned by Okta.
+// Without opt.Until the System Log never ends, so Export stops at the first
+// empty page.
+func (s *LogService) Export(ctx context.Context, opt *LogListOptions, w io.Writer) error {
+	var o LogListOptions
+	if opt != nil {
+		o = *opt
+	}
+
+	for {
+		u, err := addOptions("/api/v1/logs", &o)
+		if err != nil {
+			return err
+		}
+
+		var page []json.RawMessage
+		resp, err := s.client.call(ctx, "GET", u, nil, &page)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		for _, event := range page {
+			buf.Reset()
+			if err := json.Compact(&buf, event); err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+
+			if _, err := buf.WriteTo(w); err != nil {
+				return err
+			}
+		}
+
+		if o.After = cursorOf(resp); o.After == "" || len(page) == 0 {
+			return nil
+		}
+	}
+}
+
+// Since calls fn with each System Log event published at or after t, oldest
+// first, page by page, until the first empty page or until fn returns an
+// error, which Since returns.
+//
+// Like Okta's since parameter, Since is inclusive: the System Log has many
+// events published in the same millisecond, so skipping those published at t
+// would miss the ones the previous run didn't handle. Passing the Published
+// time of the last event handled by the previous run hands its events of
+// that millisecond to fn again, to be recognized by their UUID. See Resume
+// to handle each event once.
+func (s *LogService) Since(ctx context.Context, t time.Time, fn func(*LogEvent) error) error {
+	opt := &LogListOptions{
+		Since:       OktaTime(t),
+		ListOptions: ListOptions{SortOrder: "ASCENDING"},
+	}
+
+	for {
+		events, resp, err := s.List(ctx, opt)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		if opt.After = cursorOf(resp); opt.After == "" || len(events) == 0 {
+			return nil
+		}
+	}
+}
+
+// Resume calls fn with each System Log event following the cursor after, as
+// returned by the previous call, or published since t when after is empty,
+// oldest first, until the first empty page. It returns the cursor to resume
+// from, to be stored for the next run: events are handled once, even those
+// published in the same millisecond. If fn returns an error, the returned
+// cursor is the one of the page being handled, so that its events are
+// handled again on resumption.
+func (s *LogService) Resume(ctx context.Context, after string, t time.Time, fn func(*LogEvent) error) (string, error) {
+	opt := &LogListOptions{
+		ListOptions: ListOptions{After: after, SortOrder: "ASCENDING"},
+	}
+	if after == "" {
+		opt.Since = OktaTime(t)
+	}
+
+	for {
+		events, resp, err := s.List(ctx, opt)
+		if err != nil {
+			return opt.After, err
+		}
+
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return opt.After, err
+			}
+		}
+
+		next := cursorOf(resp)
+		if next == "" || len(events) == 0 {
+			if next == "" {
+				next = opt.After
+			}
+			return next, nil
+		}
+		opt.After = next
+	}
+}
+
+// tailInterval is the default polling interval of Tail, so that it doesn't
+// spend the rate limit of the System Log polling as fast as it can.
+const tailInterval = 10 * time.Second
+
+// Tail calls fn with each System Log event matching opt, page by page, and
+// keeps polling for new events once it has caught up. An empty page only
+// means that there is no new event yet: as long as Okta returns a next link,
+// Tail waits interval and requests it again. Tail returns when there is no
+// next link, which only happens when opt has an Until bound, when fn returns
+// an error, or when ctx is done. A non-positive interval polls every
+// tailInterval.
+func (s *LogService) Tail(ctx context.Context, opt *LogListOptions, interval time.Duration, fn func(*LogEvent) error) error {
+	var o LogListOptions
+	if opt != nil {
+		o = *opt
+	}
+	if interval <= 0 {
+		interval = tailInterval
+	}
+
+	for {
+		events, resp, err := s.List(ctx, &o)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		if o.After = cursorOf(resp); o.After == "" {
+			return nil
+		}
+		if len(events) > 0 {
+			continue
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTailWaitsBetweenEmptyPages(t *testing.T) {
+	c, mux := setup(t)
+
+	mux.HandleFunc("/api/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<`+c.BaseURL.String()+`/api/v1/logs?after=1>; rel="next"`)
+		w.Write([]byte(`[]`))
+	})
+
+	var waits []time.Duration
+	sleep = func(ctx context.Context, d time.Duration) error {
+		if waits = append(waits, d); len(waits) == 2 {
+			return context.Canceled
+		}
+		return nil
+	}
+	t.Cleanup(func() { sleep = sleepFor })
+
+	err := c.Log.Tail(context.Background(), nil, 0, func(*LogEvent) error { return nil })
+	if err != context.Canceled {
+		t.Fatalf("Tail = %v, want %v", err, context.Canceled)
+	}
+
+	for _, d := range waits {
+		if d != tailInterval {
+			t.Errorf("waited %v between empty pages, want %v", d, tailInterval)
+		}
+	}
+}
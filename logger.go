@@ -0,0 +1,78 @@
+package okta
+
+import (
+	"net/http"
+	"strings"
+)
+
+// A Logger logs the requests sent to Okta. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logRequest logs a request sent to Okta, along with the Okta request ID of
+// its response and the trace ID carried by its context under TraceIDKey.
+func (c *Client) logRequest(req *http.Request, resp *http.Response, err error) {
+	if c.Logger == nil {
+		return
+	}
+
+	var traceID interface{}
+	if c.TraceIDKey != nil {
+		traceID = req.Context().Value(c.TraceIDKey)
+	}
+
+	if err != nil {
+		c.Logger.Printf("okta: %v %v: %v trace_id=%v", req.Method, req.URL.Path, err, traceID)
+		return
+	}
+
+	c.Logger.Printf("okta: %v %v %v request_id=%v trace_id=%v",
+		req.Method, req.URL.Path, resp.StatusCode, resp.Header.Get(HeaderRequestID), traceID)
+}
+
+// logDeprecations logs the deprecations of resp, once per endpoint and client.
+// The endpoint is identified by its method and route, see route.
+func (c *Client) logDeprecations(resp *Response) {
+	if c.Logger == nil || len(resp.Deprecations) == 0 || c.deprecationsLogged == nil {
+		return
+	}
+
+	endpoint := resp.Request.Method + " " + route(resp.Request.URL.Path)
+	if _, logged := c.deprecationsLogged.LoadOrStore(endpoint, true); logged {
+		return
+	}
+
+	c.Logger.Printf("okta: %v is deprecated: %v", endpoint, strings.Join(resp.Deprecations, "; "))
+}
+
+// route returns the route of the API path p, with its IDs replaced by {id},
+// as in /api/v1/users/{id}/factors, so that the endpoints called for many
+// users or groups are told apart from each other, but not by resource.
+// The segments of Okta routes are words, such as authorizationServers or
+// forgot_password, or versions, such as v1 and oauth2: any other segment,
+// such as an ID or a login, is taken for an ID.
+func route(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if s != "" && !isRouteSegment(s) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// isRouteSegment reports whether s is a word or a version.
+func isRouteSegment(s string) bool {
+	if s == "oauth2" || len(s) > 1 && s[0] == 'v' && strings.Trim(s[1:], "0123456789") == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
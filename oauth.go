@@ -0,0 +1,332 @@
+package okta
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	oauthTokenPath     = "/oauth2/v1/token"
+	oauthAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// oauthAssertionTTL is the lifetime of the client assertions, which Okta
+	// caps at one hour.
+	oauthAssertionTTL = 5 * time.Minute
+
+	// oauthExpiryDelta is how long before its expiry an access token is
+	// renewed, so that it doesn't expire while a request is in flight.
+	oauthExpiryDelta = 30 * time.Second
+
+	// oauthDefaultTTL is the lifetime assumed for an access token issued
+	// without expires_in, the default lifetime of the Okta access tokens.
+	// A token revoked earlier is renewed on its first 401.
+	oauthDefaultTTL = time.Hour
+)
+
+// NewWithOAuth returns a new Okta client, configured by opts, authenticating
+// as the OAuth 2.0 service app clientID instead of with an API token.
+//
+// The client asks Okta for an access token granting scopes, such as
+// ScopeUsersRead, with the client credentials flow: it signs a JWT assertion
+// with privateKey, whose public key must be registered on the app. RSA keys
+// sign with RS256, and ECDSA keys with ES256, ES384 or ES512 depending on
+// their curve. The token is cached until shortly before it expires, and
+// renewed once if Okta rejects it earlier, e.g. because it was revoked.
+//
+// See RequiredScope for the scope of each endpoint.
+func NewWithOAuth(clientID string, privateKey crypto.Signer, scopes []string, organisation string, opts ...Option) (*Client, error) {
+	if clientID == "" {
+		return nil, errors.New("okta: missing OAuth client ID")
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("okta: missing OAuth scopes")
+	}
+	for _, scope := range scopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\n") {
+			return nil, fmt.Errorf("okta: invalid OAuth scope %q", scope)
+		}
+	}
+
+	alg, err := signingAlgorithm(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c := New("", organisation, opts...)
+	c.oauth = &oauthCredentials{
+		clientID: clientID,
+		key:      privateKey,
+		alg:      alg,
+		scopes:   append([]string(nil), scopes...),
+		token:    make(chan *oauthToken, 1),
+	}
+	c.oauth.token <- nil
+
+	return c, nil
+}
+
+// oauthCredentials are the credentials of a client created by NewWithOAuth,
+// and the access token they were last exchanged for.
+type oauthCredentials struct {
+	clientID string
+	key      crypto.Signer
+	alg      string
+	scopes   []string
+
+	// token holds the cached token, nil until one is issued. Receiving it
+	// locks the cache, so that concurrent requests issue a single token.
+	token chan *oauthToken
+}
+
+// oauthToken is an access token issued by the token endpoint.
+type oauthToken struct {
+	TokenType   string `json:"token_type"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+
+	expiry time.Time
+}
+
+// authorization returns the value of the Authorization header of t.
+func (t *oauthToken) authorization() string {
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	return tokenType + " " + t.AccessToken
+}
+
+// accessToken returns the cached access token, issuing a new one if there is
+// none or it is about to expire. If rejected isn't empty, it is the
+// Authorization of a request that Okta rejected: the cached token is renewed
+// if it is still the rejected one.
+func (c *Client) accessToken(ctx context.Context, rejected string) (*oauthToken, error) {
+	var token *oauthToken
+	select {
+	case token = <-c.oauth.token:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if token == nil || !now().Before(token.expiry) || token.authorization() == rejected {
+		issued, err := c.issueToken(ctx)
+		if err != nil {
+			c.oauth.token <- token
+			return nil, err
+		}
+		token = issued
+	}
+
+	c.oauth.token <- token
+	return token, nil
+}
+
+// issueToken exchanges a new client assertion for an access token, within
+// the Timeout of the client, as AddAuthorization runs before Do applies it.
+func (c *Client) issueToken(ctx context.Context) (*oauthToken, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	tokenURL := c.resolve(&url.URL{Path: oauthTokenPath})
+	assertion, err := c.oauth.assertion(tokenURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"scope":                 {strings.Join(c.oauth.scopes, " ")},
+		"client_assertion_type": {oauthAssertionType},
+		"client_assertion":      {assertion},
+	}
+	req, err := c.NewRequestURL("POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	// The options of the call the token is issued for, such as its headers
+	// or its dumper, don't apply to the token request.
+	ctx = context.WithValue(ctx, requestOptionsKey{}, []RequestOption{
+		WithContentType("application/x-www-form-urlencoded"),
+	})
+	resp, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
+		_ = resp.Body.Close()
+	}()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	token := new(oauthToken)
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("okta: token endpoint returned no access token")
+	}
+	token.expiry = now().Add(tokenLifetime(token.ExpiresIn))
+
+	return token, nil
+}
+
+// tokenLifetime returns how long a token issued for expiresIn seconds is
+// cached: until oauthExpiryDelta before it expires, or half its lifetime if
+// it is too short for that, so that a token is never cached already expired.
+func tokenLifetime(expiresIn int) time.Duration {
+	ttl := time.Duration(expiresIn) * time.Second
+	if ttl <= 0 {
+		ttl = oauthDefaultTTL
+	}
+
+	if ttl <= 2*oauthExpiryDelta {
+		return ttl / 2
+	}
+	return ttl - oauthExpiryDelta
+}
+
+// assertion returns a new client assertion for the token endpoint at audience.
+func (o *oauthCredentials) assertion(audience string) (string, error) {
+	var jti [16]byte
+	if _, err := rand.Read(jti[:]); err != nil {
+		return "", err
+	}
+
+	issued := now()
+	header, err := json.Marshal(map[string]string{"alg": o.alg, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss": o.clientID,
+		"sub": o.clientID,
+		"aud": audience,
+		"iat": issued.Unix(),
+		"exp": issued.Add(oauthAssertionTTL).Unix(),
+		"jti": hex.EncodeToString(jti[:]),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature, err := sign(o.key, o.alg, []byte(signed))
+	if err != nil {
+		return "", err
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// signingAlgorithm returns the JWS algorithm signing with key.
+func signingAlgorithm(key crypto.Signer) (string, error) {
+	if key == nil {
+		return "", errors.New("okta: missing OAuth private key")
+	}
+
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		return "RS256", nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return "ES256", nil
+		case elliptic.P384():
+			return "ES384", nil
+		case elliptic.P521():
+			return "ES512", nil
+		}
+	}
+
+	return "", fmt.Errorf("okta: unsupported OAuth private key %T", key.Public())
+}
+
+// sign returns the JWS signature of data with key for alg.
+func sign(key crypto.Signer, alg string, data []byte) ([]byte, error) {
+	var h hash.Hash
+	var hashFunc crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		h, hashFunc = sha256.New(), crypto.SHA256
+	case "ES384":
+		h, hashFunc = sha512.New384(), crypto.SHA384
+	default:
+		h, hashFunc = sha512.New(), crypto.SHA512
+	}
+	h.Write(data)
+
+	signature, err := key.Sign(rand.Reader, h.Sum(nil), hashFunc)
+	if err != nil || alg == "RS256" {
+		return signature, err
+	}
+
+	// ECDSA signers return an ASN.1 signature, whereas JWS concatenates
+	// r and s, each padded to the size of the curve.
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(signature, &rs); err != nil {
+		return nil, err
+	}
+	size := (key.Public().(*ecdsa.PublicKey).Curve.Params().BitSize + 7) / 8
+	jws := make([]byte, 2*size)
+	rs.R.FillBytes(jws[:size])
+	rs.S.FillBytes(jws[size:])
+
+	return jws, nil
+}
+
+// sendAuthorized sends req once, as send does. If Okta rejects the access
+// token of an OAuth client before it expires, a new token is issued and req
+// is sent again with it.
+func (c *Client) sendAuthorized(ctx context.Context, req *http.Request) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || c.oauth == nil {
+		return resp, err
+	}
+
+	rejected := req.Header.Get(HeaderAuthorization)
+	if rejected == "" || req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	token, tokenErr := c.accessToken(ctx, rejected)
+	if tokenErr != nil {
+		return resp, nil
+	}
+	if req.GetBody != nil {
+		if req.Body, tokenErr = req.GetBody(); tokenErr != nil {
+			return resp, nil
+		}
+	}
+
+	_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
+	_ = resp.Body.Close()
+	req.Header.Set(HeaderAuthorization, token.authorization())
+
+	return c.send(req)
+}
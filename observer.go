@@ -0,0 +1,16 @@
+package okta
+
+import "time"
+
+// An Observer is notified of every HTTP request sent to Okta, including
+// retries, for example to export request rate, error rate and latency metrics.
+type Observer interface {
+	// ObserveRequest is called once a request completed. status is the
+	// HTTP status of the response, or 0 if no response was received.
+	// path is the request path, which includes resource IDs.
+	ObserveRequest(method, path string, status int, dur time.Duration)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) ObserveRequest(method, path string, status int, dur time.Duration) {}
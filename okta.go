@@ -4,18 +4,34 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-querystring/query"
+	"github.com/tomnomnom/linkheader"
 )
 
+// ErrResponseTooLarge is returned when a response body exceeds Client.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("okta: response body too large")
+
+// ErrForeignNextLink is returned when following a next link whose host isn't the
+// host of Client.BaseURL, as a forged Link header could redirect the client,
+// and its token, to another server.
+var ErrForeignNextLink = errors.New("okta: next link points to another host")
+
 const (
 	baseURL = "https://%s.okta.com/"
+
+	defaultUserAgent  = "okta-go"
+	defaultAPIVersion = "v1"
 )
 
 type service struct {
@@ -23,40 +39,353 @@ type service struct {
 }
 
 // A Client interacts with Okta.
+//
+// A Client is safe for concurrent use, provided its exported fields are set
+// before its first request and left unchanged afterwards: they are read by
+// every request without locking. Prefer configuring it through the options
+// of New; to change the configuration later, use a new Client, such as one
+// returned by WithOrg.
 type Client struct {
 	client *http.Client
 
+	// BaseURL is the URL of the organisation. It may have a path, such as
+	// https://proxy.internal/okta/ behind a reverse proxy: request paths are
+	// joined under it, whether they start with a slash or not. The proxy must
+	// then rewrite the Link headers of Okta, as next links to another host
+	// aren't followed.
 	BaseURL *url.URL
 
 	organisation string
 	apiToken     string
+	oauth        *oauthCredentials // set by NewWithOAuth
 
-	// User agent used when communicating with the Okta api.
+	// User agent used when communicating with the Okta api,
+	// "okta-go" by default.
 	UserAgent string
 
+	// APIVersion is the version of the Okta API called by the services,
+	// "v1" by default. Relative request paths starting with /api/v1/ are
+	// rewritten to it, to opt into a newer or beta version of the API.
+	APIVersion string
+
+	// MaxRetries is the number of times a request is retried after a
+	// transient failure, by default a connection error, a 429 or a 5xx
+	// response (see RetryPolicy). Retries are disabled by default. A retry
+	// whose wait would end past the deadline of the context isn't attempted:
+	// the last response or error is returned at once instead.
+	MaxRetries int
+
+	// RetryableMethods is the set of HTTP methods which are retried.
+	// It defaults to the idempotent methods GET, HEAD, PUT and DELETE.
+	// POST requests are never retried unless added here, since resending
+	// them could create a resource twice.
+	RetryableMethods map[string]bool
+
+	// RetryBackoff, if set, returns the wait before the given retry attempt,
+	// starting at 0, when RetryPolicy leaves it to the client. It defaults to
+	// an exponential backoff from 500ms, capped at 30s.
+	RetryBackoff func(attempt int) time.Duration
+
+	// RetryPolicy, if set, decides whether a request of a retryable method is
+	// retried after it returned resp or err, and how long to wait before. A
+	// zero wait means an exponential backoff. It defaults to DefaultRetryPolicy.
+	RetryPolicy func(resp *http.Response, err error) (retry bool, wait time.Duration)
+
+	// StrictDecoding makes Do fail when a response has fields that the
+	// decoded type doesn't model, which helps spotting Okta schema changes
+	// during development. Types with their own UnmarshalJSON method, such as
+	// User, LogEvent and the other resources with timestamps, always decode
+	// leniently.
+	StrictDecoding bool
+
+	// RequestInterceptor, if set, is called with every request right before
+	// it is sent, for example to inject tracing headers from ctx.
+	RequestInterceptor func(ctx context.Context, req *http.Request)
+
+	// Observer is notified of every request sent. It does nothing by default.
+	Observer Observer
+
+	// MaxResponseBytes, if positive, is the max size of the response bodies
+	// read by Do. Reading a larger body fails with ErrResponseTooLarge.
+	// Response bodies aren't limited by default.
+	MaxResponseBytes int64
+
+	// MaxConcurrency, if positive, is the max number of requests in flight at
+	// once, from sending a request until its response is decoded, retries
+	// included. Further calls wait for a free slot, or for their context to
+	// be done. It is read on the first request; clients returned by WithOrg
+	// share the limit.
+	MaxConcurrency int
+	limiter        *limiter
+
+	// OnRateLimitNear, if set, is called after each response whose rate limit
+	// is used at RateLimitWarnThreshold or more, e.g. 0.9 once 90% of the
+	// requests of the window are used, with the remaining and total number of
+	// requests of the window. It lets callers slow down before getting 429s.
+	OnRateLimitNear        func(remaining, limit int)
+	RateLimitWarnThreshold float64
+
+	// Timeout, if positive, is the deadline of the calls whose context has
+	// none, covering the retries and the reading of the response body.
+	// A deadline set on the context always takes precedence, even if later.
+	Timeout time.Duration
+
+	// DisableBodyDrain stops Do from reading the rest of the response bodies,
+	// up to 512 bytes, before closing them. The drain lets the Transport reuse
+	// the connection; it is always skipped when the body is copied to an io.Writer.
+	DisableBodyDrain bool
+
+	// Logger, if set, logs every request sent, including retries, with the
+	// Okta request ID of its response.
+	Logger Logger
+
+	// deprecationsLogged holds the endpoints whose deprecations Logger has logged.
+	deprecationsLogged *sync.Map
+
+	// UserCache, if set, is consulted and populated by UserService.GetUser,
+	// and so by the helpers built on it such as GetMany and ListWithActors,
+	// so that a job resolving the same users again doesn't fetch them twice.
+	// The updates and lifecycle operations of UserService evict the users
+	// they change, and WaitForStatus and Offboard always fetch the current
+	// user; changes made outside the client aren't seen until the cached user
+	// expires. Cached users are shared between callers, which must not modify
+	// them. See NewUserCache.
+	UserCache UserCache
+
+	// TraceIDKey, if set, is the context key of the caller's trace ID,
+	// which Logger logs along with the Okta request ID to correlate them.
+	TraceIDKey interface{}
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
-	User  *UserService
-	Group *GroupService
+	User     *UserService
+	UserType *UserTypeService
+	Group    *GroupService
+	Factor   *FactorService
+	App      *ApplicationService
+	Log      *LogService
+
+	AuthorizationServer *AuthorizationServerService
+	Behavior            *BehaviorService
+	Schema              *SchemaService
+	IDX                 *IDXService
+	Batch               *BatchService
+	Role                *RoleService
+	EmailServer         *EmailServerService
+	Org                 *OrgService
+	AgentPool           *AgentPoolService
+	Authenticator       *AuthenticatorService
+	Policy              *PolicyService
+	IdP                 *IdentityProviderService
+	EmailDomain         *EmailDomainService
+	Captcha             *CaptchaService
 }
 
-// New returns a new Okta client.
-func New(apiToken, organisation string) *Client {
+// New returns a new Okta client, configured by opts.
+func New(apiToken, organisation string, opts ...Option) *Client {
 	c := &Client{
-		client:       http.DefaultClient,
-		apiToken:     apiToken,
-		organisation: organisation,
+		client:           http.DefaultClient,
+		apiToken:         apiToken,
+		organisation:     organisation,
+		UserAgent:        defaultUserAgent,
+		APIVersion:       defaultAPIVersion,
+		RetryableMethods: defaultRetryableMethods(),
+		Observer:         nopObserver{},
+
+		limiter:            new(limiter),
+		deprecationsLogged: new(sync.Map),
 	}
-	c.common.client = c
 	c.BaseURL, _ = url.Parse(buildURL(baseURL, organisation))
+	c.initServices()
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// An Option configures a Client created with New.
+type Option func(*Client)
+
+// WithHTTPClient makes the client send its requests with httpClient,
+// instead of http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = httpClient
+	}
+}
+
+// WithRetry makes the client retry the transient failures of the requests
+// of a retryable method up to maxRetries times, see MaxRetries and
+// DefaultRetryPolicy: rate limited requests wait until the rate limit resets,
+// and the others wait as long as backoff returns for the attempt, starting
+// at 0. A nil backoff keeps the default exponential backoff.
+func WithRetry(maxRetries int, backoff func(attempt int) time.Duration) Option {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
+		c.RetryBackoff = backoff
+	}
+}
+
+// WithUserAgentSuffix appends suffix to the User-Agent of the client, so that
+// a library wrapping it can identify itself, as in "okta-go myapp/3.4".
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		c.UserAgent += " " + suffix
+	}
+}
+
+func (c *Client) initServices() {
+	c.common.client = c
 	c.User = (*UserService)(&c.common)
+	c.UserType = (*UserTypeService)(&c.common)
 	c.Group = (*GroupService)(&c.common)
+	c.Factor = (*FactorService)(&c.common)
+	c.App = (*ApplicationService)(&c.common)
+	c.Log = (*LogService)(&c.common)
+	c.AuthorizationServer = (*AuthorizationServerService)(&c.common)
+	c.Behavior = (*BehaviorService)(&c.common)
+	c.Schema = (*SchemaService)(&c.common)
+	c.IDX = (*IDXService)(&c.common)
+	c.Batch = (*BatchService)(&c.common)
+	c.Role = (*RoleService)(&c.common)
+	c.EmailServer = (*EmailServerService)(&c.common)
+	c.Org = (*OrgService)(&c.common)
+	c.AgentPool = (*AgentPoolService)(&c.common)
+	c.Authenticator = (*AuthenticatorService)(&c.common)
+	c.Policy = (*PolicyService)(&c.common)
+	c.IdP = (*IdentityProviderService)(&c.common)
+	c.EmailDomain = (*EmailDomainService)(&c.common)
+	c.Captcha = (*CaptchaService)(&c.common)
+}
 
-	return c
+// WithOrg returns a copy of c for another organisation and API token.
+// The copy shares the HTTP client of c and copies its configuration,
+// such as the user agent and retry settings, but not its UserCache.
+// The copy of a client created by NewWithOAuth authenticates with apiToken
+// too, as a service app and its access tokens belong to a single org.
+// It is the way to administer several orgs: since each org has its own API
+// tokens, a request can't target another org than the one of its client.
+func (c *Client) WithOrg(apiToken, organisation string) *Client {
+	clone := *c
+	clone.apiToken = apiToken
+	clone.organisation = organisation
+	clone.BaseURL, _ = url.Parse(buildURL(baseURL, organisation))
+	clone.UserCache = nil // the users of c belong to another org
+	clone.oauth = nil
+
+	clone.RetryableMethods = make(map[string]bool, len(c.RetryableMethods))
+	for m, ok := range c.RetryableMethods {
+		clone.RetryableMethods[m] = ok
+	}
+
+	clone.initServices()
+	return &clone
+}
+
+// String describes the client without revealing its API token,
+// so that logging a client doesn't leak the token.
+func (c *Client) String() string {
+	token := `""`
+	if c.apiToken != "" {
+		token = "[REDACTED]"
+	}
+	if c.oauth != nil {
+		token = fmt.Sprintf("[OAUTH %s]", c.oauth.clientID)
+	}
+
+	var base string
+	if c.BaseURL != nil {
+		base = c.BaseURL.String()
+	}
+
+	return fmt.Sprintf("okta.Client{organisation: %q, BaseURL: %q, apiToken: %s}", c.organisation, base, token)
+}
+
+// GoString implements fmt.GoStringer so that %#v doesn't reveal the API token either.
+func (c *Client) GoString() string {
+	return c.String()
+}
+
+// SessionCookieRedirectURL returns the URL to redirect a browser to so that it
+// exchanges sessionToken, obtained from Authn, for an Okta session cookie
+// before landing on redirectURL.
+func (c *Client) SessionCookieRedirectURL(sessionToken, redirectURL string) string {
+	u := c.resolve(&url.URL{Path: "/login/sessionCookieRedirect"})
+	u.RawQuery = url.Values{
+		"token":       {sessionToken},
+		"redirectUrl": {redirectURL},
+	}.Encode()
+
+	return u.String()
+}
+
+// ListOptions are the pagination and sorting options shared by list endpoints.
+// They are embedded in the options of the list methods.
+type ListOptions struct {
+	// Limit is the max number of results in a page.
+	Limit int `url:"limit,omitempty"`
+
+	// After is the cursor of the page to return, taken from the
+	// next link of the previous page.
+	After string `url:"after,omitempty"`
+
+	SortBy    string `url:"sortBy,omitempty"`
+	SortOrder string `url:"sortOrder,omitempty"` // asc or desc
+}
+
+// VerifyAccess checks that the client can call Okta by fetching the user
+// owning the credentials, for example to fail fast on startup.
+// The returned error tells apart rejected and insufficient credentials.
+//
+// A service app has no user: for a client created by NewWithOAuth, it gets an
+// access token instead, which Okta refuses to issue if the credentials are
+// invalid or a scope isn't granted to the app. The admin roles which the
+// endpoints may also require aren't checked.
+func (c *Client) VerifyAccess(ctx context.Context) error {
+	if c.oauth != nil {
+		_, err := c.accessToken(ctx, "")
+
+		var errResp *ErrorResponse
+		if errors.As(err, &errResp) {
+			return fmt.Errorf("okta: OAuth credentials or scopes are rejected: %w", err)
+		}
+		return err
+	}
+
+	_, err := c.call(ctx, "GET", "/api/v1/users/me", nil, nil)
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		switch errResp.Response.StatusCode {
+		case http.StatusUnauthorized:
+			return fmt.Errorf("okta: credentials are invalid or expired: %w", err)
+		case http.StatusForbidden:
+			return fmt.Errorf("okta: credentials lack the permission to read users: %w", err)
+		}
+	}
+
+	return err
+}
+
+// Ping checks that the org is reachable, without credentials, by fetching
+// its public /.well-known/okta-organization document. Unlike VerifyAccess, it
+// succeeds whatever the API token, which makes it fit for readiness probes.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := c.NewRequest("GET", "/.well-known/okta-organization", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(ctx, req, nil)
+	return err
 }
 
 // addOptions adds the parameters in opt as URL query parameters to s. opt
-// must be a struct whose fields may contain "url" tags.
+// must be a struct whose fields may contain "url" tags. Slices are encoded as
+// a repeated key by default; Okta parameters taking a list, such as expand,
+// want the values comma-separated instead, which the ",comma" tag option does.
 func addOptions(s string, opt interface{}) (string, error) {
 	v := reflect.ValueOf(opt)
 	if v.Kind() == reflect.Ptr && v.IsNil() {
@@ -78,11 +407,27 @@ func addOptions(s string, opt interface{}) (string, error) {
 }
 
 // AddAuthorization injects the Authorization header to the request.
-// If the client doesn't has an oauthToken, a new token is issed.
-// If the token is expired, it is automatically refreshed.
+// If the client, created by NewWithOAuth, has no access token yet,
+// a new token is issued. If the token is expired, it is automatically refreshed.
+// A *ScopeError is returned if the scopes of such a client don't grant the
+// one required by the endpoint of req, instead of sending it to get a 403.
 func (c *Client) AddAuthorization(ctx context.Context, req *http.Request) error {
-	if c.apiToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("SSWS %s", c.apiToken))
+	if c.oauth != nil {
+		if !c.HasScopeFor(req.Method, req.URL.Path) {
+			return &ScopeError{
+				Method: req.Method,
+				Path:   req.URL.Path,
+				Scope:  RequiredScope(req.Method, req.URL.Path),
+			}
+		}
+
+		token, err := c.accessToken(ctx, "")
+		if err != nil {
+			return err
+		}
+		req.Header.Set(HeaderAuthorization, token.authorization())
+	} else if c.apiToken != "" {
+		req.Header.Set(HeaderAuthorization, fmt.Sprintf("SSWS %s", c.apiToken))
 	}
 
 	return nil
@@ -97,30 +442,34 @@ func (c *Client) AddAuthorization(ctx context.Context, req *http.Request) error
 // The provided ctx must be non-nil. If it is canceled or times out,
 // ctx.Err() will be returned.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
-	req = req.WithContext(ctx)
-
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	resp, err := c.client.Do(req)
+	release, err := c.acquire(ctx)
 	if err != nil {
-		// If we got an error, and the context has been canceled,
-		// the context's error is probably more useful.
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+		return nil, err
+	}
+	defer release()
 
+	resp, err := c.roundTrip(ctx, req)
+	if err != nil {
 		return nil, err
 	}
+	c.limitBody(resp)
 
+	_, toWriter := v.(io.Writer)
+	drain := !c.DisableBodyDrain && !toWriter
 	defer func() {
 		// Drain up to 512 bytes and close the body to let the Transport reuse the connection.
-		_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
+		// A body copied to an io.Writer has already been read to the end.
+		if drain {
+			_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
+		}
 		_ = resp.Body.Close()
 	}()
 	response := newResponse(resp)
+	c.logDeprecations(response)
+	c.checkRateLimit(response)
 
 	err = checkResponse(resp)
 	if err != nil {
@@ -131,45 +480,420 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
-			io.Copy(w, resp.Body)
+			_, err = io.Copy(w, resp.Body)
 		} else {
-			err = json.NewDecoder(resp.Body).Decode(v)
-			if err == io.EOF {
-				err = nil // ignore EOF errors caused by empty response body.
-			}
+			err = c.decode(resp.Body, v)
 		}
 	}
+	if p, ok := v.(linkedPage); ok && err == nil {
+		response.setBodyLinks(p.links())
+	}
 
 	return response, err
 }
 
+// acquire waits for a free request slot when MaxConcurrency is set.
+// The returned func releases it.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.MaxConcurrency <= 0 || c.limiter == nil {
+		return func() {}, nil
+	}
+
+	return c.limiter.acquire(ctx, c.MaxConcurrency)
+}
+
+// withTimeout returns ctx with the Timeout of the client as deadline,
+// unless ctx already has one.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.Timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+// DoWithRaw behaves like Do, but buffers the whole response body and returns
+// it alongside the decoded value. The raw bytes are returned even when an API
+// error occurred.
+func (c *Client) DoWithRaw(ctx context.Context, req *http.Request, v interface{}) (*Response, []byte, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	resp, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.limitBody(resp)
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	response := newResponse(resp)
+	c.logDeprecations(response)
+	c.checkRateLimit(response)
+	if err != nil {
+		return response, nil, err
+	}
+
+	// checkResponse reads the error from the body, so hand it a fresh reader.
+	resp.Body = ioutil.NopCloser(bytes.NewReader(raw))
+	err = checkResponse(resp)
+	if err != nil {
+		return response, raw, err
+	}
+
+	if v != nil {
+		err = c.decode(bytes.NewReader(raw), v)
+	}
+	if p, ok := v.(linkedPage); ok && err == nil {
+		response.setBodyLinks(p.links())
+	}
+
+	return response, raw, err
+}
+
+// roundTrip sends req with the default headers and returns the raw HTTP
+// response, retrying transient failures as configured on the client.
+// The caller is responsible for closing the response body.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	opts := requestOptionsFromContext(ctx)
+	for k, v := range opts.header {
+		req.Header[k] = v
+	}
+
+	if c.RequestInterceptor != nil {
+		c.RequestInterceptor(ctx, req)
+	}
+
+	if opts.dumper != nil {
+		dumpRequest(req, opts.dumper)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendAuthorized(ctx, req)
+		received := now()
+		retry, wait := c.shouldRetry(ctx, req, resp, err)
+		if wait <= 0 {
+			wait = c.retryBackoff(attempt)
+		}
+		wait = remainingWait(wait, received)
+
+		// A retry which can't be sent before the deadline of ctx would only
+		// fail with ctx.Err(): return the outcome of this attempt instead.
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now()) <= wait {
+			retry = false
+		}
+
+		if retry && attempt < c.MaxRetries && !opts.noRetry {
+			if resp != nil {
+				_, _ = io.CopyN(ioutil.Discard, resp.Body, 512)
+				_ = resp.Body.Close()
+			}
+
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+
+			if req.GetBody != nil {
+				if req.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if err != nil {
+			// If we got an error, and the context has been canceled,
+			// the context's error is probably more useful.
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+
+			return nil, err
+		}
+
+		return resp, nil
+	}
+}
+
+// send sends req once, reporting it to the observer.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.Do(req)
+
+	if c.Observer != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		c.Observer.ObserveRequest(req.Method, req.URL.Path, status, time.Since(start))
+	}
+	c.logRequest(req, resp, err)
+
+	return resp, err
+}
+
+// limitBody limits the size of the body of resp to MaxResponseBytes.
+func (c *Client) limitBody(resp *http.Response) {
+	if c.MaxResponseBytes > 0 {
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: c.MaxResponseBytes}
+	}
+}
+
+// limitedBody fails with ErrResponseTooLarge when more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Only fail if there is actually more to read.
+		var probe [1]byte
+		for {
+			n, err := b.ReadCloser.Read(probe[:])
+			if n > 0 {
+				return 0, ErrResponseTooLarge
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// decode JSON decodes r into v. Numbers decoded into a generic target,
+// such as a *map[string]interface{}, are json.Number, so that large
+// integers keep their precision.
+func (c *Client) decode(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	if c.StrictDecoding {
+		dec.DisallowUnknownFields()
+	}
+
+	switch v.(type) {
+	case *interface{}, *map[string]interface{}, *[]interface{}, *[]map[string]interface{}:
+		dec.UseNumber()
+	}
+
+	err := dec.Decode(v)
+	if err == io.EOF {
+		err = nil // ignore EOF errors caused by empty response body.
+	}
+
+	return err
+}
+
+// decodeAs decodes the generic JSON value m, such as the settings of a
+// policy, into v, whose type depends on the type of the resource of m.
+func decodeAs(m map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// unmarshalNumbers decodes data into v like json.Unmarshal, but decodes the
+// numbers of the generic values of v as json.Number: large integers, such as
+// epoch milliseconds, don't fit a float64. The UnmarshalJSON methods use it,
+// as the decoder of Do doesn't apply to them.
+func unmarshalNumbers(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
 func newResponse(resp *http.Response) *Response {
-	return &Response{Response: resp}
+	r := &Response{
+		Response:  resp,
+		RequestID: resp.Header.Get(HeaderRequestID),
+		Rate:      rateOf(resp.Header),
+	}
+	warnings, deprecations := deprecationsOf(resp.Header)
+	r.Deprecations = append(append(r.Deprecations, warnings...), deprecations...)
+	if location, err := resp.Location(); err == nil {
+		r.Location = location
+	}
+	r.setPagination()
+
+	return r
+}
+
+// nextURL returns the URL of the next page of a paginated response,
+// or an empty string if resp is the last page.
+// Okta returns the next URL to page in the Link header,
+// see https://developer.okta.com/docs/reference/api-overview/#link-header.
+// A few newer endpoints return it in the _links object of the body instead,
+// which is used when the header has no next link.
+func nextURL(resp *Response) string {
+	return linkURL(resp, "next")
+}
+
+// linkURL returns the URL of the link rel of resp, such as next or self,
+// from its Link header or else its body links, or "" if it has none.
+func linkURL(resp *Response, rel string) string {
+	links := linkheader.Parse(strings.Join(resp.Header["Link"], ","))
+	for _, link := range links {
+		if link.Rel == rel {
+			return link.URL
+		}
+	}
+
+	return resp.bodyLinks[rel].Href
+}
+
+// linkedPage is implemented by the bodies of the endpoints which return their
+// pagination links in a _links object, such as the IAM endpoints, by
+// embedding pageLinks. Do keeps these links in the Response for nextURL.
+type linkedPage interface {
+	links() map[string]Link
+}
+
+// pageLinks is the _links object of a linkedPage.
+type pageLinks struct {
+	Links map[string]Link `json:"_links"`
+}
+
+func (p *pageLinks) links() map[string]Link {
+	return p.Links
+}
+
+// nextPage returns the next link of resp, or "" when there is no next page.
+// It fails with ErrForeignNextLink when the link leaves BaseURL's host.
+func (c *Client) nextPage(resp *Response) (string, error) {
+	next := nextURL(resp)
+	if next == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(next)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != "" && !strings.EqualFold(u.Host, c.BaseURL.Host) {
+		return "", ErrForeignNextLink
+	}
+
+	return next, nil
+}
+
+// cursorOf returns the after cursor of the next link of resp,
+// or "" when there is no next page.
+func cursorOf(resp *Response) string {
+	return queryParam(nextURL(resp), "after")
+}
+
+// queryParam returns the query parameter key of rawURL, or "" if it has none.
+func queryParam(rawURL, key string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Query().Get(key)
 }
 
 // NewRequest instantiate a new http.Request from a method, url and body.
-// The body (if provided) is automatically Marshalled into JSON.
+// A relative urlStr is resolved against BaseURL. An absolute one, such as the
+// href of a Link from a webhook payload or a _links object, is used as is;
+// since AddAuthorization attaches the API token whatever the host, check that
+// such a URL belongs to the org before following it.
+// The body (if provided) is automatically Marshalled into JSON, unless it is
+// an io.Reader, which is sent as is; see WithContentType for its media type.
+// The Content-Length of the request is set for JSON bodies, for
+// *bytes.Buffer, *bytes.Reader and *strings.Reader bodies, and for the
+// readers returned by SizedBody, so that it isn't sent chunked.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
-	rel, err := url.Parse(urlStr)
+	u, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
 	}
 
-	u := c.BaseURL.ResolveReference(rel)
+	return c.NewRequestURL(method, u, body)
+}
 
-	var buf io.ReadWriter
-	if body != nil {
-		buf = new(bytes.Buffer)
-		err := json.NewEncoder(buf).Encode(body)
-		if err != nil {
+// resolve returns the relative URL rel joined under BaseURL, so that the path
+// of BaseURL is kept: with https://proxy.internal/okta as BaseURL, both
+// /api/v1/users and api/v1/users resolve to https://proxy.internal/okta/api/v1/users.
+func (c *Client) resolve(rel *url.URL) *url.URL {
+	base := *c.BaseURL
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+		if base.RawPath != "" {
+			base.RawPath += "/"
+		}
+	}
+
+	// Trim RawPath too, so that escaped segments such as a%2Fb are kept.
+	r := *rel
+	r.Path = strings.TrimPrefix(r.Path, "/")
+	r.RawPath = strings.TrimPrefix(r.RawPath, "/")
+
+	return base.ResolveReference(&r)
+}
+
+// NewRequestURL behaves like NewRequest with an already parsed URL,
+// such as a next link. An absolute u is used as is, its query left
+// untouched; a relative one is joined under BaseURL without modifying u.
+func (c *Client) NewRequestURL(method string, u *url.URL, body interface{}) (*http.Request, error) {
+	if !u.IsAbs() {
+		rel := *u
+		if c.APIVersion != "" && c.APIVersion != defaultAPIVersion {
+			prefix := "/api/" + defaultAPIVersion + "/"
+			if p := strings.TrimPrefix(rel.Path, prefix); p != rel.Path {
+				rel.Path = "/api/" + c.APIVersion + "/" + p
+				if rel.RawPath != "" {
+					rel.RawPath = "/api/" + c.APIVersion + "/" + strings.TrimPrefix(rel.RawPath, prefix)
+				}
+			}
+		}
+
+		u = c.resolve(&rel)
+	}
+
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case *sizedBody:
+		r = b.Reader
+	case io.Reader:
+		r = b
+	default:
+		buf := new(bytes.Buffer)
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
 			return nil, err
 		}
+		r = buf
 	}
 
-	req, err := http.NewRequest(method, u.String(), buf)
+	req, err := http.NewRequest(method, u.String(), r)
 	if err != nil {
 		return nil, err
 	}
+	if b, ok := body.(*sizedBody); ok {
+		req.ContentLength = b.size
+	}
 
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -183,6 +907,61 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	return req, nil
 }
 
+// sizedBody is a request body of known size, see SizedBody.
+type sizedBody struct {
+	io.Reader
+	size int64
+}
+
+// SizedBody returns a request body reading size bytes from r, for which
+// NewRequest sets the Content-Length of the request. Some proxies reject
+// the chunked requests sent for bodies of unknown size.
+func SizedBody(r io.Reader, size int64) io.Reader {
+	return &sizedBody{r, size}
+}
+
+// NewRequestWithQuery behaves like NewRequest, adding the parameters of opt to
+// the URL as with addOptions. opt must be a struct whose fields may contain
+// "url" tags, or nil.
+func (c *Client) NewRequestWithQuery(method, urlStr string, opt, body interface{}) (*http.Request, error) {
+	u, err := addOptions(urlStr, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.NewRequest(method, u, body)
+}
+
+// call builds an authorized request to urlStr and sends it with Do,
+// decoding the response into v.
+func (c *Client) call(ctx context.Context, method, urlStr string, body, v interface{}) (*Response, error) {
+	req, err := c.NewRequest(method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.AddAuthorization(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return c.Do(ctx, req, v)
+}
+
+// lifecycle runs the lifecycle operation action, such as activate, of the
+// resource at basePath, with the query parameters of opt if not nil, and
+// decodes the response into v.
+func (c *Client) lifecycle(ctx context.Context, basePath, action string, opt, v interface{}) (*Response, error) {
+	u := basePath + "/lifecycle/" + url.PathEscape(action)
+	if opt != nil {
+		var err error
+		if u, err = addOptions(u, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.call(ctx, "POST", u, nil, v)
+}
+
 // checkResponse checks the *http.Response.
 // HTTP status codes ranging from 200 to 299 are considered are successes.
 // Otherwise an error happen, and the error gets unmarshalled and returned into the error.
@@ -191,35 +970,198 @@ func checkResponse(r *http.Response) error {
 		return nil
 	}
 
-	errorResponse := &ErrorResponse{Response: r}
+	errorResponse := &ErrorResponse{
+		Response:  r,
+		RequestID: r.Header.Get(HeaderRequestID),
+	}
 	data, err := ioutil.ReadAll(r.Body)
 	if err == nil && data != nil {
 		errorResponse.Code = int64(r.StatusCode)
 		errorResponse.Type = http.StatusText(r.StatusCode)
 		errorResponse.Message = string(data)
+
+		var body struct {
+			ErrorCode    string       `json:"errorCode"`
+			ErrorSummary string       `json:"errorSummary"`
+			ErrorID      string       `json:"errorId"`
+			ErrorCauses  []ErrorCause `json:"errorCauses"`
+		}
+		switch {
+		case len(data) == 0:
+		case json.Unmarshal(data, &body) != nil:
+			errorResponse.NonJSON = true
+		default:
+			errorResponse.ErrorCode = body.ErrorCode
+			errorResponse.ErrorSummary = body.ErrorSummary
+			errorResponse.ErrorID = body.ErrorID
+			errorResponse.ErrorCauses = body.ErrorCauses
+			if body.ErrorSummary != "" {
+				errorResponse.Message = body.ErrorSummary
+			}
+		}
+	}
+
+	if r.StatusCode == http.StatusTooManyRequests || errorResponse.ErrorCode == errorCodeRateLimited {
+		return &RateLimitError{ErrorResponse: errorResponse, Reset: rateOf(r.Header).Reset}
 	}
 
-	// TODO: handle the different errors here, such as MFA, Rate limit, etc...
+	if r.StatusCode == http.StatusUnauthorized {
+		return &AuthenticationError{ErrorResponse: errorResponse}
+	}
+
+	// MFA is not an error: see AuthnResponse.IsMFARequired.
 	return errorResponse
 }
 
 // Response embeds a *http.Response.
 type Response struct {
 	*http.Response
+
+	// RequestID is the X-Okta-Request-Id header of the response,
+	// which Okta support asks for when investigating a request.
+	RequestID string
+
+	// Rate is the rate limit of the endpoint, which the request counts against.
+	Rate Rate
+
+	// Deprecations are the Warning, Deprecation and Okta-Deprecated headers of the response,
+	// set when Okta plans to remove the endpoint or one of its parameters.
+	Deprecations []string
+
+	// Location is the Location header of the response, resolved against the
+	// request URL, such as the URL of the resource a Create call created.
+	// It is nil when the response has none.
+	Location *url.URL
+
+	// Pagination tells whether there are pages before and after the page of
+	// the response, and the cursor of the previous one.
+	Pagination Pagination
+
+	// NextURL is the URL of the next page, and NextCursor its after cursor,
+	// to set as ListOptions.After; both are empty on the last page. SelfURL
+	// is the URL of the page itself. They come from the Link header, whose
+	// malformed entries are ignored, or else from the body links.
+	NextURL    string
+	NextCursor string
+	SelfURL    string
+
+	// bodyLinks are the pagination links of a linkedPage body.
+	bodyLinks map[string]Link
+}
+
+// setBodyLinks sets the links of a linkedPage body, and the pagination they
+// describe when the Link header doesn't.
+func (r *Response) setBodyLinks(links map[string]Link) {
+	r.bodyLinks = links
+	r.setPagination()
+}
+
+// setPagination sets the pagination fields of r from its links.
+func (r *Response) setPagination() {
+	r.Pagination = paginationOf(r)
+	r.NextURL = nextURL(r)
+	r.NextCursor = queryParam(r.NextURL, "after")
+	r.SelfURL = linkURL(r, "self")
 }
 
 // An ErrorResponse reports an error caused by an API request.
 type ErrorResponse struct {
-	Response *http.Response // HTTP response that caused this error
-	Code     int64
-	Type     string
-	Message  string
+	Response  *http.Response // HTTP response that caused this error
+	Code      int64
+	Type      string
+	Message   string // errorSummary of the body, or the whole body if NonJSON
+	RequestID string // X-Okta-Request-Id of the failed request
+
+	// ErrorCode identifies the error, e.g. E0000007 for a missing resource,
+	// see https://developer.okta.com/docs/reference/error-codes/.
+	ErrorCode    string
+	ErrorSummary string
+	ErrorID      string
+	ErrorCauses  []ErrorCause
+
+	// NonJSON is true when the body isn't an Okta error, such as the HTML
+	// page of a proxy answering in front of Okta.
+	NonJSON bool
 }
 
+// ErrorCause details an ErrorResponse, e.g. which profile attribute is invalid.
+type ErrorCause struct {
+	ErrorSummary string `json:"errorSummary"`
+}
+
+// maxErrorMessage is the max length of the message included by ErrorResponse.Error.
+const maxErrorMessage = 512
+
+// Error describes the failed request by its method and URL only:
+// the request headers, which carry the credentials, are never included.
+// The message lists the error causes, such as the password policy rules
+// a new password breaks.
 func (r *ErrorResponse) Error() string {
-	return fmt.Sprintf("%v %v: Okta responsed with code %d, type %v and message %v",
+	message := r.Message
+	if len(r.ErrorCauses) > 0 {
+		causes := make([]string, len(r.ErrorCauses))
+		for i, c := range r.ErrorCauses {
+			causes[i] = c.ErrorSummary
+		}
+		message += ": " + strings.Join(causes, "; ")
+	}
+	if len(message) > maxErrorMessage {
+		message = message[:maxErrorMessage] + "..."
+	}
+	if r.ErrorCode != "" {
+		message = r.ErrorCode + " " + message
+	}
+
+	return fmt.Sprintf("%v %v: Okta responsed with code %d, type %v and message %v (request id %v)",
 		r.Response.Request.Method, r.Response.Request.URL,
-		r.Response.StatusCode, r.Type, r.Message)
+		r.Response.StatusCode, r.Type, message, r.RequestID)
+}
+
+// errorCodeRateLimited is the Okta error code of an exceeded rate limit, which
+// Okta may send with another status than 429.
+const errorCodeRateLimited = "E0000047"
+
+// RateLimitError is returned when Okta rejects a request because a rate limit
+// is exceeded, either with a 429 status or with the E0000047 error code.
+// It unwraps to its *ErrorResponse.
+type RateLimitError struct {
+	*ErrorResponse
+	Reset time.Time // when the rate limit window resets, zero if unknown
+}
+
+// Unwrap returns the underlying *ErrorResponse.
+func (r *RateLimitError) Unwrap() error {
+	return r.ErrorResponse
+}
+
+// IsRateLimited reports whether err is caused by an exceeded rate limit.
+func IsRateLimited(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// AuthenticationError is returned when Okta rejects the credentials of a
+// request with a 401 status, such as an invalid, expired or revoked token,
+// or a wrong password given to Authn. It unwraps to its *ErrorResponse.
+type AuthenticationError struct {
+	*ErrorResponse
+}
+
+// Unwrap returns the underlying *ErrorResponse.
+func (r *AuthenticationError) Unwrap() error {
+	return r.ErrorResponse
+}
+
+// IsAuthenticationFailed reports whether err is caused by rejected credentials.
+func IsAuthenticationFailed(err error) bool {
+	var authErr *AuthenticationError
+	return errors.As(err, &authErr)
+}
+
+// isNotFound reports whether err is an API error caused by a missing resource.
+func isNotFound(err error) bool {
+	var errResp *ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound
 }
 
 func buildURL(baseURL string, args ...interface{}) string {
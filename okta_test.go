@@ -0,0 +1,95 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// setup returns a client sending its requests to a test server, whose
+// handlers are registered on mux. The server is closed with the test.
+func setup(t *testing.T) (*Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := New("token", "example")
+	c.BaseURL, _ = url.Parse(srv.URL)
+
+	return c, mux
+}
+
+// TestConcurrentDo checks, when run with -race, that concurrent calls only
+// read the configuration of the client.
+func TestConcurrentDo(t *testing.T) {
+	c, mux := setup(t)
+	c.MaxConcurrency = 4
+	c.MaxRetries = 1
+	c.Logger = log.New(ioutil.Discard, "", 0)
+	c.OnRateLimitNear = func(remaining, limit int) {}
+	c.RateLimitWarnThreshold = 0.5
+	c.UserCache = NewUserCache(0)
+
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(HeaderAuthorization); got != "SSWS token" {
+			t.Errorf("Authorization = %q, want SSWS token", got)
+		}
+
+		w.Header().Set(HeaderRateLimitLimit, "100")
+		w.Header().Set(HeaderRateLimitRemaining, "10")
+		fmt.Fprintf(w, `{"id": %q, "status": "ACTIVE"}`, r.URL.Path[len("/api/v1/users/"):])
+	})
+
+	const calls = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			id := fmt.Sprintf("00u%d", i)
+			user, err := c.User.GetUser(context.Background(), id)
+			if err == nil && user.ID != id {
+				err = fmt.Errorf("got user %q, want %q", user.ID, id)
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestAddOptionsSkipsZeroValues(t *testing.T) {
+	for _, opt := range []interface{}{
+		&ListOptions{},
+		&UserListOptions{},
+		&GroupListOptions{},
+		&LogListOptions{},
+		&IdPListOptions{},
+		&AgentPoolListOptions{},
+	} {
+		u, err := addOptions("/api/v1/resources", opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if u != "/api/v1/resources" {
+			t.Errorf("addOptions(%T{}) = %q, want no query", opt, u)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httputil"
+)
+
+// A RequestOption customises the requests made by a single API call.
+// Options are attached to the context passed to the call with WithRequestOptions.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	header  http.Header
+	noRetry bool
+	dumper  func(dump []byte)
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx carrying opts, in addition to the
+// options already carried by ctx. They apply to every request sent with the
+// returned context:
+//
+//	ctx = okta.WithRequestOptions(ctx, okta.WithHeader("X-Device-Fingerprint", fp))
+//	err := c.User.UpdateCustomAttributes(ctx, id, attributes)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	prev, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	all := make([]RequestOption, 0, len(prev)+len(opts))
+	all = append(all, prev...)
+	all = append(all, opts...)
+	return context.WithValue(ctx, requestOptionsKey{}, all)
+}
+
+func requestOptionsFromContext(ctx context.Context) *requestOptions {
+	o := &requestOptions{header: make(http.Header)}
+	opts, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithHeader sets the header key to value on the request.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header on the request, and makes
+// it retryable whatever its method, so that a create can safely be resent when
+// its response is lost.
+//
+// Okta doesn't document idempotency keys on any of its endpoints, so the key
+// only protects against duplicates when the requests go through a gateway or
+// proxy that honours it.
+func WithIdempotencyKey(key string) RequestOption {
+	return WithHeader(headerIdempotencyKey, key)
+}
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+// WithAccept overrides the Accept header of the request, which defaults to
+// application/json. Pair it with an io.Writer passed to Do to receive
+// non-JSON bodies such as CSV exports.
+func WithAccept(mediaType string) RequestOption {
+	return WithHeader("Accept", mediaType)
+}
+
+// WithContentType overrides the Content-Type header of the request, which
+// defaults to application/json, for endpoints expecting another media type
+// such as application/merge-patch+json. The body is still encoded as JSON.
+func WithContentType(mediaType string) RequestOption {
+	return WithHeader("Content-Type", mediaType)
+}
+
+// WithNoRetry sends the request only once, whatever the MaxRetries of the
+// client, for calls which must fail fast such as pre-flight checks.
+func WithNoRetry() RequestOption {
+	return func(o *requestOptions) {
+		o.noRetry = true
+	}
+}
+
+// WithRequestDumper calls dumper with the request as sent on the wire, body
+// included, right before it is sent, to compare it with the documentation of
+// Okta when an endpoint rejects it. The Authorization header is redacted.
+// Retries send the same request, so it is only dumped once.
+func WithRequestDumper(dumper func(dump []byte)) RequestOption {
+	return func(o *requestOptions) {
+		o.dumper = dumper
+	}
+}
+
+// dumpRequest calls dumper with the dump of req, its Authorization redacted.
+// Dumping restores the body of req, so that it can still be sent.
+func dumpRequest(req *http.Request, dumper func(dump []byte)) {
+	auth, ok := req.Header[HeaderAuthorization]
+	if ok {
+		req.Header.Set(HeaderAuthorization, "[REDACTED]")
+		defer func() { req.Header[HeaderAuthorization] = auth }()
+	}
+
+	if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+		dumper(dump)
+	}
+}
@@ -0,0 +1,143 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OrgService reads and manages the settings of the organisation.
+type OrgService service
+
+// RateLimitSettings are the rate limit settings of the organisation. Okta
+// doesn't expose the limits of each endpoint, which depend on the org's
+// plan; see the X-Rate-Limit-* headers of the responses instead.
+type RateLimitSettings struct {
+	// AdminNotifications tells whether admins are notified when a rate limit
+	// is reached.
+	AdminNotifications bool
+
+	// WarningThreshold is the percentage of a rate limit which, once used,
+	// logs a warning event in the System Log.
+	WarningThreshold int
+
+	// PerClient is how rate limits are shared between the clients of
+	// OAuth applications.
+	PerClient *PerClientRateLimitSettings
+}
+
+// PerClientRateLimitSettings is the per-client rate limit mode of the
+// organisation: ENFORCE, PREVIEW or DISABLE, overridable per use case such
+// as LOGIN_PAGE or OAUTH2_AUTHORIZE.
+type PerClientRateLimitSettings struct {
+	DefaultMode          string            `json:"defaultMode"`
+	UseCaseModeOverrides map[string]string `json:"useCaseModeOverrides,omitempty"`
+}
+
+// GetRateLimitSettings returns the rate limit settings of the organisation.
+// The returned *Response is the one of the last settings read.
+func (s *OrgService) GetRateLimitSettings(ctx context.Context) (*RateLimitSettings, *Response, error) {
+	var notifications struct {
+		NotificationsEnabled bool `json:"notificationsEnabled"`
+	}
+	resp, err := s.client.call(ctx, "GET", "/api/v1/rate-limit-settings/admin-notifications", nil, &notifications)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var threshold struct {
+		WarningThreshold int `json:"warningThreshold"`
+	}
+	resp, err = s.client.call(ctx, "GET", "/api/v1/rate-limit-settings/warning-threshold", nil, &threshold)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var perClient PerClientRateLimitSettings
+	resp, err = s.client.call(ctx, "GET", "/api/v1/rate-limit-settings/per-client", nil, &perClient)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &RateLimitSettings{
+		AdminNotifications: notifications.NotificationsEnabled,
+		WarningThreshold:   threshold.WarningThreshold,
+		PerClient:          &perClient,
+	}, resp, nil
+}
+
+// Principal types of PrincipalRateLimit.
+const (
+	PrincipalTypeAPIToken    = "SSWS_TOKEN"
+	PrincipalTypeOAuthClient = "OAUTH_CLIENT"
+)
+
+// PrincipalRateLimit is the share of the org rate limits granted to a single
+// API token or OAuth client, its principal.
+type PrincipalRateLimit struct {
+	ID            string `json:"id"`
+	OrgID         string `json:"orgId"`
+	PrincipalID   string `json:"principalId"`
+	PrincipalType string `json:"principalType"`
+
+	// DefaultPercentage is the percentage of each org rate limit that the
+	// principal can use, and DefaultConcurrencyPercentage the one of the
+	// concurrent requests limit.
+	DefaultPercentage            int `json:"defaultPercentage"`
+	DefaultConcurrencyPercentage int `json:"defaultConcurrencyPercentage"`
+
+	Created       time.Time `json:"created"`
+	CreatedBy     string    `json:"createdBy"`
+	LastUpdate    time.Time `json:"lastUpdate"`
+	LastUpdatedBy string    `json:"lastUpdatedBy"`
+}
+
+// UnmarshalJSON decodes a principal rate limit, mapping null or empty
+// timestamps to the zero time.
+func (p *PrincipalRateLimit) UnmarshalJSON(data []byte) error {
+	type alias PrincipalRateLimit
+	aux := &struct {
+		*alias
+		Created    timestamp `json:"created"`
+		LastUpdate timestamp `json:"lastUpdate"`
+	}{alias: (*alias)(p)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	p.Created = time.Time(aux.Created)
+	p.LastUpdate = time.Time(aux.LastUpdate)
+	return nil
+}
+
+// ListPrincipalRateLimits returns the rate limits of the principals of type
+// principalType, PrincipalTypeAPIToken or PrincipalTypeOAuthClient.
+func (s *OrgService) ListPrincipalRateLimits(ctx context.Context, principalType string) ([]*PrincipalRateLimit, *Response, error) {
+	u, err := addOptions("/api/v1/principal-rate-limits", &struct {
+		Filter string `url:"filter"`
+	}{fmt.Sprintf("principalType eq %v", quoteFilterValue(principalType))})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var limits []*PrincipalRateLimit
+	resp, err := s.client.call(ctx, "GET", u, nil, &limits)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return limits, resp, nil
+}
+
+// GetPrincipalRateLimit returns a principal rate limit by its ID.
+func (s *OrgService) GetPrincipalRateLimit(ctx context.Context, id string) (*PrincipalRateLimit, *Response, error) {
+	var limit PrincipalRateLimit
+	resp, err := s.client.call(ctx, "GET", fmt.Sprintf("/api/v1/principal-rate-limits/%v", id), nil, &limit)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &limit, resp, nil
+}
@@ -0,0 +1,48 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// PatchOp is an operation of a JSONPatch. Value is used by the add, replace
+// and test operations, From by move and copy.
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+	From  string      `json:"from,omitempty"`
+}
+
+// MarshalJSON encodes op, omitting Value for the remove, move and copy
+// operations only: a false, zero, empty or null value still means something
+// to add, replace and test.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	type alias PatchOp
+	switch op.Op {
+	case "remove", "move", "copy":
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+			From string `json:"from,omitempty"`
+		}{op.Op, op.Path, op.From})
+	}
+
+	return json.Marshal(alias(op))
+}
+
+// JSONPatch is a JSON Patch document, see RFC 6902.
+type JSONPatch []PatchOp
+
+const mediaTypeJSONPatch = "application/json-patch+json"
+
+// Patch sends patch to urlStr with the application/json-patch+json media
+// type, decoding the response into v.
+//
+// Few Okta endpoints accept JSON Patch: the schema and policy endpoints in
+// particular only take partial objects, see SchemaService.UpdateUserSchema.
+func (c *Client) Patch(ctx context.Context, urlStr string, patch JSONPatch, v interface{}) (*Response, error) {
+	ctx = WithRequestOptions(ctx, WithContentType(mediaTypeJSONPatch))
+
+	return c.call(ctx, "PATCH", urlStr, patch, v)
+}
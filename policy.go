@@ -0,0 +1,159 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PolicyService reads the policies of the organisation.
+//
+// Okta doesn't tell which policy applies to a given user. The nearest
+// endpoint, POST /api/v1/policies/simulate, evaluates the policies of an app
+// instance against a policy context (user, groups, zones, risk), so it needs
+// an app and isn't wrapped here; call it with Client.NewRequest and Do.
+type PolicyService service
+
+// Policy types.
+const (
+	PolicyTypeOktaSignOn      = "OKTA_SIGN_ON"
+	PolicyTypePassword        = "PASSWORD"
+	PolicyTypeMFAEnroll       = "MFA_ENROLL"
+	PolicyTypeAccess          = "ACCESS_POLICY"
+	PolicyTypeProfileEnroll   = "PROFILE_ENROLLMENT"
+	PolicyTypeIDPDiscovery    = "IDP_DISCOVERY"
+	PolicyTypeEntityRisk      = "ENTITY_RISK"
+	PolicyTypePostAuthSession = "POST_AUTH_SESSION"
+)
+
+// Policy is an Okta policy, such as the access policy governing the sign-on
+// to an application. The keys of Conditions and Settings depend on Type.
+type Policy struct {
+	ID          string                 `json:"id,omitempty"`
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Priority    int                    `json:"priority,omitempty"`
+	System      bool                   `json:"system,omitempty"`
+	Conditions  map[string]interface{} `json:"conditions,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Created     time.Time              `json:"created"`
+	LastUpdated time.Time              `json:"lastUpdated"`
+	Links       map[string]Link        `json:"_links,omitempty"`
+}
+
+// UnmarshalJSON decodes a policy, mapping null or empty timestamps to the zero
+// time.
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	type alias Policy
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(p)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	p.Created = time.Time(aux.Created)
+	p.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// PasswordSettings returns the Settings of a password policy, and false if p
+// isn't a password policy. Changes to the returned settings don't change p.
+func (p *Policy) PasswordSettings() (*PasswordPolicySettings, bool) {
+	if p.Type != PolicyTypePassword {
+		return nil, false
+	}
+
+	var settings PasswordPolicySettings
+	if err := decodeAs(p.Settings, &settings); err != nil {
+		return nil, false
+	}
+
+	return &settings, true
+}
+
+// PasswordPolicySettings are the settings of a password policy, the rules
+// of the passwords of the users it applies to.
+type PasswordPolicySettings struct {
+	Password struct {
+		Complexity PasswordComplexity `json:"complexity"`
+		Age        PasswordAge        `json:"age"`
+		Lockout    PasswordLockout    `json:"lockout"`
+	} `json:"password"`
+
+	// Recovery and Delegation are left undecoded, e.g. the recovery factors.
+	Recovery   map[string]interface{} `json:"recovery,omitempty"`
+	Delegation map[string]interface{} `json:"delegation,omitempty"`
+}
+
+// PasswordComplexity are the characters a password must have.
+type PasswordComplexity struct {
+	MinLength         int      `json:"minLength"`
+	MinLowerCase      int      `json:"minLowerCase"`
+	MinUpperCase      int      `json:"minUpperCase"`
+	MinNumber         int      `json:"minNumber"`
+	MinSymbol         int      `json:"minSymbol"`
+	ExcludeUsername   bool     `json:"excludeUsername"`
+	ExcludeAttributes []string `json:"excludeAttributes,omitempty"`
+	Dictionary        struct {
+		Common struct {
+			Exclude bool `json:"exclude"`
+		} `json:"common"`
+	} `json:"dictionary"`
+}
+
+// PasswordAge rules when a password can and must be changed.
+type PasswordAge struct {
+	MaxAgeDays     int `json:"maxAgeDays"`
+	ExpireWarnDays int `json:"expireWarnDays"`
+	MinAgeMinutes  int `json:"minAgeMinutes"`
+	HistoryCount   int `json:"historyCount"`
+}
+
+// PasswordLockout rules when a user is locked out after failed sign-ins.
+type PasswordLockout struct {
+	MaxAttempts                     int      `json:"maxAttempts"`
+	AutoUnlockMinutes               int      `json:"autoUnlockMinutes"`
+	ShowLockoutFailures             bool     `json:"showLockoutFailures"`
+	UserLockoutNotificationChannels []string `json:"userLockoutNotificationChannels,omitempty"`
+}
+
+func policyURL(id string) string {
+	return fmt.Sprintf("/api/v1/policies/%v", id)
+}
+
+// List returns the policies of type policyType, such as PolicyTypePassword,
+// by priority.
+func (s *PolicyService) List(ctx context.Context, policyType string) ([]*Policy, *Response, error) {
+	u, err := addOptions("/api/v1/policies", &struct {
+		Type string `url:"type"`
+	}{policyType})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var policies []*Policy
+	resp, err := s.client.call(ctx, "GET", u, nil, &policies)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return policies, resp, nil
+}
+
+// Get returns a policy.
+func (s *PolicyService) Get(ctx context.Context, id string) (*Policy, *Response, error) {
+	var policy Policy
+	resp, err := s.client.call(ctx, "GET", policyURL(id), nil, &policy)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &policy, resp, nil
+}
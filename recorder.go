@@ -0,0 +1,125 @@
+package okta
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recording is a response saved by RecordingTransport.
+type recording struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// recordingFile returns the file of the response to req in dir. Requests
+// are matched by method, path and query, so that each page is recorded.
+func recordingFile(dir string, req *http.Request) string {
+	name := req.Method + strings.Replace(req.URL.Path, "/", "_", -1)
+	if req.URL.RawQuery != "" {
+		sum := sha1.Sum([]byte(req.URL.RawQuery))
+		name += "_" + hex.EncodeToString(sum[:4])
+	}
+
+	return filepath.Join(dir, name+".json")
+}
+
+// RecordingTransport is an http.RoundTripper saving every response to a file
+// of Dir, for ReplayTransport to serve them back in tests. A response
+// replaces the previous one of the same request. The request headers, which
+// carry the credentials, aren't saved; response bodies are saved as is.
+//
+//	c := okta.New(apiToken, org, okta.WithHTTPClient(&http.Client{
+//		Transport: &okta.RecordingTransport{Dir: "testdata/okta"},
+//	}))
+type RecordingTransport struct {
+	Dir string
+
+	// Transport sends the requests, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// RoundTrip sends req and saves its response.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	data, err := json.MarshalIndent(&recording{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       string(body),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(recordingFile(t.Dir, req), data, 0644); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport is an http.RoundTripper serving the responses saved by
+// RecordingTransport in Dir, without sending any request. A request which
+// wasn't recorded fails.
+type ReplayTransport struct {
+	Dir string
+}
+
+// RoundTrip returns the recorded response to req.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	data, err := ioutil.ReadFile(recordingFile(t.Dir, req))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("okta: no recorded response for %v %v", req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var r recording
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header,
+		Body:          ioutil.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
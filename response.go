@@ -0,0 +1,133 @@
+package okta
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// OktaMeta gathers the Okta headers of a response, for logging.
+type OktaMeta struct {
+	RequestID string
+
+	// RateLimitLimit and RateLimitRemaining are -1 when the response has no
+	// rate limit headers. RateLimitReset is when the rate limit window resets.
+	RateLimitLimit     int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+
+	// Warnings and Deprecations are the headers announcing that Okta plans
+	// to remove the endpoint or one of its parameters: together, they are
+	// the Deprecations of the Response.
+	Warnings     []string // Warning headers
+	Deprecations []string // Deprecation and Okta-Deprecated headers
+}
+
+// RawHeaders returns the headers of the response, or nil if r has no HTTP response.
+func (r *Response) RawHeaders() http.Header {
+	if r == nil || r.Response == nil {
+		return nil
+	}
+
+	return r.Response.Header
+}
+
+// OktaMeta returns the Okta headers of the response.
+func (r *Response) OktaMeta() OktaMeta {
+	h := r.RawHeaders()
+	rate := rateOf(h)
+	warnings, deprecations := deprecationsOf(h)
+
+	return OktaMeta{
+		RequestID:          h.Get(HeaderRequestID),
+		RateLimitLimit:     rate.Limit,
+		RateLimitRemaining: rate.Remaining,
+		RateLimitReset:     rate.Reset,
+		Warnings:           warnings,
+		Deprecations:       deprecations,
+	}
+}
+
+// deprecationsOf returns the Warning headers of h, and its Deprecation and
+// Okta-Deprecated headers.
+func deprecationsOf(h http.Header) (warnings, deprecations []string) {
+	deprecations = append(deprecations, h[HeaderDeprecation]...)
+	deprecations = append(deprecations, h[HeaderOktaDeprecated]...)
+
+	return h[HeaderWarning], deprecations
+}
+
+// Rate is the rate limit of the endpoint a response comes from, as described
+// by its X-Rate-Limit headers. Limit and Remaining are -1 when the response
+// has no rate limit headers.
+type Rate struct {
+	Limit     int       // max number of requests in the window
+	Remaining int       // requests left in the window
+	Reset     time.Time // when the window resets, zero if unknown
+}
+
+// rateOf returns the rate limit described by the headers h.
+func rateOf(h http.Header) Rate {
+	rate := Rate{
+		Limit:     headerInt(h, HeaderRateLimitLimit),
+		Remaining: headerInt(h, HeaderRateLimitRemaining),
+	}
+
+	if reset := headerInt(h, HeaderRateLimitReset); reset >= 0 {
+		rate.Reset = time.Unix(int64(reset), 0)
+	}
+
+	return rate
+}
+
+// checkRateLimit calls OnRateLimitNear when the rate limit of resp is used
+// beyond RateLimitWarnThreshold.
+func (c *Client) checkRateLimit(resp *Response) {
+	if c.OnRateLimitNear == nil || c.RateLimitWarnThreshold <= 0 {
+		return
+	}
+
+	rate := resp.Rate
+	if rate.Limit <= 0 || rate.Remaining < 0 {
+		return
+	}
+
+	used := float64(rate.Limit-rate.Remaining) / float64(rate.Limit)
+	if used >= c.RateLimitWarnThreshold {
+		c.OnRateLimitNear(rate.Remaining, rate.Limit)
+	}
+}
+
+// headerInt returns the integer value of the header key, or -1.
+func headerInt(h http.Header, key string) int {
+	n, err := strconv.Atoi(h.Get(key))
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// Pagination describes the pages around the page of a paginated response,
+// from its Link header or its body links. Okta rarely returns prev links.
+// The next page and the page itself are described by the NextURL,
+// NextCursor and SelfURL of the Response.
+type Pagination struct {
+	HasNext    bool
+	HasPrev    bool
+	PrevCursor string // before parameter of the prev link, or else its after one
+}
+
+func paginationOf(resp *Response) Pagination {
+	prev := linkURL(resp, "prev")
+
+	p := Pagination{
+		HasNext: nextURL(resp) != "",
+		HasPrev: prev != "",
+	}
+	if p.PrevCursor = queryParam(prev, "before"); p.PrevCursor == "" {
+		p.PrevCursor = queryParam(prev, "after")
+	}
+
+	return p
+}
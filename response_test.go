@@ -0,0 +1,30 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestOktaMetaDeprecations(t *testing.T) {
+	c, mux := setup(t)
+
+	mux.HandleFunc("/api/v1/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderOktaDeprecated, "the q parameter is deprecated")
+		w.Write([]byte(`[]`))
+	})
+
+	_, resp, err := c.Group.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"the q parameter is deprecated"}
+	if meta := resp.OktaMeta(); !reflect.DeepEqual(meta.Deprecations, want) {
+		t.Errorf("OktaMeta().Deprecations = %q, want %q", meta.Deprecations, want)
+	}
+	if !reflect.DeepEqual(resp.Deprecations, want) {
+		t.Errorf("Deprecations = %q, want %q", resp.Deprecations, want)
+	}
+}
@@ -0,0 +1,203 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryBaseDelay is the wait before the first retry, doubled on each attempt.
+const retryBaseDelay = 500 * time.Millisecond
+
+// defaultRetryableMethods are the idempotent methods, which can safely be sent twice.
+func defaultRetryableMethods() map[string]bool {
+	return map[string]bool{
+		"GET":    true,
+		"HEAD":   true,
+		"PUT":    true,
+		"DELETE": true,
+	}
+}
+
+// shouldRetry reports whether req can be sent again after it returned resp or
+// err, and how long to wait before, zero meaning the default backoff.
+func (c *Client) shouldRetry(ctx context.Context, req *http.Request, resp *http.Response, err error) (bool, time.Duration) {
+	if ctx.Err() != nil {
+		return false, 0
+	}
+
+	if !c.RetryableMethods[req.Method] && req.Header.Get(headerIdempotencyKey) == "" {
+		return false, 0
+	}
+
+	// The body has already been consumed and can't be replayed.
+	if req.Body != nil && req.GetBody == nil {
+		return false, 0
+	}
+
+	policy := c.RetryPolicy
+	if policy == nil {
+		policy = DefaultRetryPolicy
+	}
+
+	return policy(resp, err)
+}
+
+// DefaultRetryPolicy retries connection errors, 5xx responses, and rate
+// limited ones: 429 responses and the 4xx ones with the E0000047 error code.
+// It waits as long as the Retry-After header or, when rate limited, until the
+// X-Rate-Limit-Reset time plus a small jitter; otherwise it lets the
+// client back off.
+func DefaultRetryPolicy(resp *http.Response, err error) (bool, time.Duration) {
+	if err != nil {
+		return true, 0
+	}
+
+	rateLimited := resp.StatusCode == http.StatusTooManyRequests || isRateLimitBody(resp)
+	if !rateLimited && resp.StatusCode < 500 {
+		return false, 0
+	}
+
+	if wait := retryAfter(resp.Header); wait > 0 {
+		return true, wait
+	}
+
+	if rateLimited {
+		if wait, ok := rateLimitWait(resp.Header); ok {
+			return true, wait
+		}
+	}
+
+	return true, 0
+}
+
+// maxPeekedBody is the max size of a body read by isRateLimitBody.
+const maxPeekedBody = 64 << 10
+
+// isRateLimitBody reports whether resp is a 4xx response whose body carries
+// the E0000047 error code. The body is read and put back for checkResponse.
+func isRateLimitBody(resp *http.Response) bool {
+	if resp.StatusCode < 400 || resp.StatusCode > 499 {
+		return false
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPeekedBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	var body struct {
+		ErrorCode string `json:"errorCode"`
+	}
+	return json.Unmarshal(data, &body) == nil && body.ErrorCode == errorCodeRateLimited
+}
+
+// retryJitter is the max random delay added to rate limit waits, so that
+// concurrent clients don't all retry at the very second the limit resets.
+const retryJitter = 250 * time.Millisecond
+
+// rateLimitWait returns the wait until the X-Rate-Limit-Reset time of header,
+// plus a jitter. The reset time is compared with the Date header, so that
+// the skew of the local clock doesn't matter.
+func rateLimitWait(header http.Header) (time.Duration, bool) {
+	reset, err := strconv.ParseInt(header.Get(HeaderRateLimitReset), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	wait := time.Unix(reset, 0).Sub(responseTime(header))
+	if wait < 0 {
+		wait = 0
+	}
+
+	return wait + time.Duration(rand.Int63n(int64(retryJitter))), true
+}
+
+// retryAfter parses the Retry-After header of header, either a number of
+// seconds or an HTTP date, returning 0 when it is missing or invalid. A date
+// is compared with the Date header, like in rateLimitWait.
+func retryAfter(header http.Header) time.Duration {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(responseTime(header))
+	}
+
+	return 0
+}
+
+// responseTime returns when a response was generated according to its Date
+// header, or now if it has none.
+func responseTime(header http.Header) time.Time {
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		return date
+	}
+
+	return now()
+}
+
+// now returns the current time. It is the clock of the retry loop, which
+// makes the wait computations testable with a fake clock.
+var now = time.Now
+
+// remainingWait returns what is left of wait, counted from received, at the
+// time of the call: the time spent since the response was received, such as
+// deciding whether to retry it, is part of the wait.
+func remainingWait(wait time.Duration, received time.Time) time.Duration {
+	if wait -= now().Sub(received); wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// maxRetryDelay caps the exponential backoff, which would otherwise
+// overflow after a few dozen attempts.
+const maxRetryDelay = 30 * time.Second
+
+// retryBackoff returns the wait before the given retry attempt (starting at 0),
+// from RetryBackoff if set.
+func (c *Client) retryBackoff(attempt int) time.Duration {
+	if c.RetryBackoff != nil {
+		return c.RetryBackoff(attempt)
+	}
+
+	if attempt >= 6 { // 500ms << 6 is past maxRetryDelay
+		return maxRetryDelay
+	}
+	return retryBaseDelay << uint(attempt)
+}
+
+// sleep waits for d, returning early with ctx.Err() if ctx is done. Like now,
+// it is a variable so that tests can wait on a fake clock.
+var sleep = sleepFor
+
+// sleepFor waits for d on the real clock, see sleep.
+func sleepFor(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
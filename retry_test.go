@@ -0,0 +1,105 @@
+package okta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setClock makes now return t for the duration of the test.
+func setClock(t *testing.T, clock time.Time) {
+	t.Helper()
+
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestRemainingWait(t *testing.T) {
+	received := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		elapsed, wait, want time.Duration
+	}{
+		{0, 10 * time.Second, 10 * time.Second},
+		{3 * time.Second, 10 * time.Second, 7 * time.Second},
+		{10 * time.Second, 10 * time.Second, 0},
+		{15 * time.Second, 10 * time.Second, 0},
+	} {
+		setClock(t, received.Add(tt.elapsed))
+
+		if got := remainingWait(tt.wait, received); got != tt.want {
+			t.Errorf("remainingWait(%v) after %v = %v, want %v", tt.wait, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, clock)
+
+	header := http.Header{"Retry-After": {clock.Add(20 * time.Second).Format(http.TimeFormat)}}
+	if got := retryAfter(header); got != 20*time.Second {
+		t.Errorf("retryAfter without Date = %v, want 20s", got)
+	}
+
+	// The Date header wins over the local clock, which may be skewed.
+	header.Set("Date", clock.Add(5*time.Second).Format(http.TimeFormat))
+	if got := retryAfter(header); got != 15*time.Second {
+		t.Errorf("retryAfter with Date = %v, want 15s", got)
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, clock)
+
+	for _, tt := range []struct {
+		reset time.Time
+		want  time.Duration
+	}{
+		{clock.Add(30 * time.Second), 30 * time.Second},
+		{clock.Add(-30 * time.Second), 0},
+	} {
+		header := http.Header{HeaderRateLimitReset: {strconv.FormatInt(tt.reset.Unix(), 10)}}
+
+		got, ok := rateLimitWait(header)
+		if !ok || got < tt.want || got >= tt.want+retryJitter {
+			t.Errorf("rateLimitWait(reset %v) = %v, %v, want %v plus jitter", tt.reset, got, ok, tt.want)
+		}
+	}
+}
+
+// TestRetryBeyondDeadline checks that a rate limit outlasting the deadline of
+// the context is returned at once instead of being waited for.
+func TestRetryBeyondDeadline(t *testing.T) {
+	c, mux := setup(t)
+	c.MaxRetries = 3
+
+	var calls int32
+	mux.HandleFunc("/api/v1/users/00u1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.User.GetUser(ctx, "00u1")
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Errorf("err = %v, want a *RateLimitError", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("returned after %v, want no wait", elapsed)
+	}
+}
@@ -0,0 +1,189 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RoleService manages the admin roles assigned to users and groups.
+type RoleService service
+
+// Role is an admin role assigned to a user, directly or through a group,
+// or to a group.
+type Role struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type"` // e.g. SUPER_ADMIN or USER_ADMIN
+	Label          string          `json:"label"`
+	Status         string          `json:"status"`
+	AssignmentType string          `json:"assignmentType"` // USER or GROUP
+	Created        time.Time       `json:"created"`
+	LastUpdated    time.Time       `json:"lastUpdated"`
+	Links          map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a role, mapping null or empty timestamps to the zero
+// time.
+func (r *Role) UnmarshalJSON(data []byte) error {
+	type alias Role
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(r)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	r.Created = time.Time(aux.Created)
+	r.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// RoleAssignment assigns an admin role. Type is the type of a standard role,
+// or CUSTOM for a custom role, which also needs the IDs of Role and of
+// the ResourceSet it applies to.
+type RoleAssignment struct {
+	Type        string `json:"type"`
+	Role        string `json:"role,omitempty"`
+	ResourceSet string `json:"resource-set,omitempty"`
+}
+
+// Permission is a permission of a custom admin role, e.g. "okta.users.read".
+// Okta identifies permissions by their Label, and returns no separate type.
+type Permission struct {
+	Label       string          `json:"label"`
+	Created     time.Time       `json:"created"`
+	LastUpdated time.Time       `json:"lastUpdated"`
+	Links       map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a permission, mapping null or empty timestamps to the
+// zero time.
+func (p *Permission) UnmarshalJSON(data []byte) error {
+	type alias Permission
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(p)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	p.Created = time.Time(aux.Created)
+	p.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// roleConcurrency is the number of users ListUsersWithRole checks at once.
+const roleConcurrency = 4
+
+// ListUserRoles returns the admin roles of a user.
+func (s *RoleService) ListUserRoles(ctx context.Context, userID string) ([]*Role, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/roles", userID)
+
+	var roles []*Role
+	resp, err := s.client.call(ctx, "GET", u, nil, &roles)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return roles, resp, nil
+}
+
+// ListPermissions returns the permissions granted by the custom admin role roleID.
+// Okta has no endpoint listing every permission available to custom roles,
+// so there is no catalog to list: the permissions are only documented, along
+// with the custom admin roles.
+func (s *RoleService) ListPermissions(ctx context.Context, roleID string) ([]*Permission, *Response, error) {
+	u := fmt.Sprintf("/api/v1/iam/roles/%v/permissions", roleID)
+
+	var list struct {
+		Permissions []*Permission `json:"permissions"`
+	}
+	resp, err := s.client.call(ctx, "GET", u, nil, &list)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return list.Permissions, resp, nil
+}
+
+// assigneesPage is a page of /api/v1/iam/assignees/users. Unlike most list
+// endpoints, Okta returns it as an object, whose next link is in _links.
+type assigneesPage struct {
+	Value []struct {
+		ID string `json:"id"`
+	} `json:"value"`
+	pageLinks
+}
+
+// ListUsersWithRole returns the users holding the admin role of the given type,
+// directly or through a group. Okta only lists the users holding any admin
+// role, so the roles of each of them are then fetched, concurrently.
+// The returned *Response is the one of the last page of admins. On error,
+// including the cancellation of ctx, the users found so far are returned
+// along with it.
+func (s *RoleService) ListUsersWithRole(ctx context.Context, roleType string) ([]*User, *Response, error) {
+	var ids []string
+	var resp *Response
+	for u := "/api/v1/iam/assignees/users"; u != ""; {
+		var page assigneesPage
+		var err error
+		if resp, err = s.client.call(ctx, "GET", u, nil, &page); err != nil {
+			return nil, resp, err
+		}
+
+		for _, a := range page.Value {
+			ids = append(ids, a.ID)
+		}
+		if u, err = s.client.nextPage(resp); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	var mu sync.Mutex
+	var users []*User
+	var err error
+	forEach(ctx, len(ids), roleConcurrency, func(i int) {
+		user, uerr := s.userWithRole(ctx, ids[i], roleType)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case uerr != nil:
+			if err == nil {
+				err = uerr
+			}
+		case user != nil:
+			users = append(users, user)
+		}
+	})
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return users, resp, err
+}
+
+// userWithRole returns the user userID if it holds the role roleType, or nil.
+func (s *RoleService) userWithRole(ctx context.Context, userID, roleType string) (*User, error) {
+	roles, _, err := s.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list roles of user %v: %w", userID, err)
+	}
+
+	for _, r := range roles {
+		if r.Type == roleType {
+			return s.client.User.GetUser(ctx, userID)
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,36 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestListPermissions(t *testing.T) {
+	c, mux := setup(t)
+
+	mux.HandleFunc("/api/v1/iam/roles/cr01/permissions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"permissions": [
+			{"label": "okta.users.read", "created": "2021-02-06T16:20:57.000Z", "lastUpdated": ""},
+			{"label": "okta.groups.manage"}
+		]}`))
+	})
+
+	permissions, _, err := c.Role.ListPermissions(context.Background(), "cr01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"okta.users.read", "okta.groups.manage"}
+	if len(permissions) != len(want) {
+		t.Fatalf("listed %d permissions, want %d", len(permissions), len(want))
+	}
+	for i, p := range permissions {
+		if p.Label != want[i] {
+			t.Errorf("permission %d = %q, want %q", i, p.Label, want[i])
+		}
+	}
+	if permissions[0].Created.IsZero() || !permissions[0].LastUpdated.IsZero() {
+		t.Errorf("timestamps = %v, %v, want a created time only", permissions[0].Created, permissions[0].LastUpdated)
+	}
+}
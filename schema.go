@@ -0,0 +1,185 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SchemaService manages the profile schemas of users, groups and app users.
+type SchemaService service
+
+// Schema is the JSON schema of a kind of profile. Only its Definitions can be updated.
+type Schema struct {
+	ID          string             `json:"id,omitempty"`
+	Schema      string             `json:"$schema,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Definitions *SchemaDefinitions `json:"definitions,omitempty"`
+	Links       map[string]Link    `json:"_links,omitempty"`
+}
+
+// UserSchema is the schema of the profile of a user type.
+type UserSchema Schema
+
+// GroupSchema is the schema of group profiles.
+type GroupSchema Schema
+
+// AppUserSchema is the schema of the profiles of the users assigned to an application.
+type AppUserSchema Schema
+
+// SchemaDefinitions holds the base attributes defined by Okta,
+// and the custom attributes defined by the organisation.
+type SchemaDefinitions struct {
+	Base   *SchemaDefinition `json:"base,omitempty"`
+	Custom *SchemaDefinition `json:"custom,omitempty"`
+}
+
+// SchemaDefinition is a set of profile attributes.
+// In an update, setting an attribute of Properties to nil removes it.
+type SchemaDefinition struct {
+	ID         string                      `json:"id,omitempty"`
+	Type       string                      `json:"type,omitempty"`
+	Properties map[string]*SchemaAttribute `json:"properties,omitempty"`
+	Required   []string                    `json:"required,omitempty"`
+}
+
+// SchemaAttribute is a profile attribute.
+type SchemaAttribute struct {
+	Title             string              `json:"title,omitempty"`
+	Type              string              `json:"type,omitempty"` // string, boolean, number, integer or array
+	Description       string              `json:"description,omitempty"`
+	Required          bool                `json:"required,omitempty"`
+	Mutability        string              `json:"mutability,omitempty"`
+	Scope             string              `json:"scope,omitempty"`
+	Unique            string              `json:"unique,omitempty"`
+	MinLength         *int                `json:"minLength,omitempty"`
+	MaxLength         *int                `json:"maxLength,omitempty"`
+	Enum              []interface{}       `json:"enum,omitempty"`
+	OneOf             []*SchemaEnum       `json:"oneOf,omitempty"`
+	Items             *SchemaAttribute    `json:"items,omitempty"`
+	Permissions       []*SchemaPermission `json:"permissions,omitempty"`
+	Master            *SchemaMaster       `json:"master,omitempty"`
+	ExternalName      string              `json:"externalName,omitempty"`
+	ExternalNamespace string              `json:"externalNamespace,omitempty"`
+}
+
+// SchemaEnum is a value allowed for an attribute, with its display name.
+type SchemaEnum struct {
+	Const interface{} `json:"const"`
+	Title string      `json:"title"`
+}
+
+// SchemaPermission is the access of a principal, such as SELF, to an attribute.
+type SchemaPermission struct {
+	Principal string `json:"principal"`
+	Action    string `json:"action"` // READ_WRITE, READ_ONLY or HIDE
+}
+
+// SchemaMaster is the source of truth of an attribute.
+type SchemaMaster struct {
+	Type string `json:"type"` // PROFILE_MASTER, OKTA or OVERRIDE
+}
+
+// GetUserSchema returns the schema of a user type,
+// "default" being the ID of the default user type.
+func (s *SchemaService) GetUserSchema(ctx context.Context, typeID string) (*UserSchema, *Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/schemas/user/%v", typeID)
+
+	var schema UserSchema
+	resp, err := s.client.call(ctx, "GET", u, nil, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &schema, resp, nil
+}
+
+// UpdateUserSchema updates the attributes of the schema of a user type
+// with the definitions of patch, and returns the updated schema.
+func (s *SchemaService) UpdateUserSchema(ctx context.Context, typeID string, patch *UserSchema) (*UserSchema, *Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/schemas/user/%v", typeID)
+
+	var schema UserSchema
+	resp, err := s.client.call(ctx, "POST", u, patch, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &schema, resp, nil
+}
+
+// GetGroupSchema returns the schema of group profiles.
+func (s *SchemaService) GetGroupSchema(ctx context.Context) (*GroupSchema, *Response, error) {
+	var schema GroupSchema
+	resp, err := s.client.call(ctx, "GET", "/api/v1/meta/schemas/group/default", nil, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &schema, resp, nil
+}
+
+// UpdateGroupSchema updates the attributes of the group schema with the
+// definitions of patch, and returns the updated schema.
+func (s *SchemaService) UpdateGroupSchema(ctx context.Context, patch *GroupSchema) (*GroupSchema, *Response, error) {
+	var schema GroupSchema
+	resp, err := s.client.call(ctx, "POST", "/api/v1/meta/schemas/group/default", patch, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &schema, resp, nil
+}
+
+// GetAppUserSchema returns the schema of the app user profiles of an application.
+func (s *SchemaService) GetAppUserSchema(ctx context.Context, appID string) (*AppUserSchema, *Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/schemas/apps/%v/default", appID)
+
+	var schema AppUserSchema
+	resp, err := s.client.call(ctx, "GET", u, nil, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &schema, resp, nil
+}
+
+// UpdateAppUserSchema updates the attributes of the app user schema of an
+// application with the definitions of patch, and returns the updated schema.
+func (s *SchemaService) UpdateAppUserSchema(ctx context.Context, appID string, patch *AppUserSchema) (*AppUserSchema, *Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/schemas/apps/%v/default", appID)
+
+	var schema AppUserSchema
+	resp, err := s.client.call(ctx, "POST", u, patch, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &schema, resp, nil
+}
+
+// FetchSchema returns the raw JSON schema at schemaURL, such as the schema
+// link of a user or group. schemaURL must be on the host of the client,
+// since the request carries its credentials.
+func (c *Client) FetchSchema(ctx context.Context, schemaURL string) (json.RawMessage, *Response, error) {
+	u, err := url.Parse(schemaURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.Host != "" && !strings.EqualFold(u.Host, c.BaseURL.Host) {
+		return nil, nil, fmt.Errorf("okta: schema URL %v isn't on host %v", schemaURL, c.BaseURL.Host)
+	}
+
+	var schema json.RawMessage
+	resp, err := c.call(ctx, "GET", schemaURL, nil, &schema)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return schema, resp, nil
+}
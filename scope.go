@@ -0,0 +1,143 @@
+package okta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OAuth 2.0 scopes of the Okta management API,
+// see https://developer.okta.com/docs/guides/implement-oauth-for-okta/scopes/.
+const (
+	ScopeUsersRead                  = "okta.users.read"
+	ScopeUsersManage                = "okta.users.manage"
+	ScopeSchemasRead                = "okta.schemas.read"
+	ScopeSchemasManage              = "okta.schemas.manage"
+	ScopeUserTypesRead              = "okta.userTypes.read"
+	ScopeUserTypesManage            = "okta.userTypes.manage"
+	ScopeGroupsRead                 = "okta.groups.read"
+	ScopeGroupsManage               = "okta.groups.manage"
+	ScopeFactorsRead                = "okta.factors.read"
+	ScopeFactorsManage              = "okta.factors.manage"
+	ScopeAppsRead                   = "okta.apps.read"
+	ScopeAppsManage                 = "okta.apps.manage"
+	ScopeDevicesRead                = "okta.devices.read"
+	ScopeDevicesManage              = "okta.devices.manage"
+	ScopeLogsRead                   = "okta.logs.read"
+	ScopeAuthorizationServersRead   = "okta.authorizationServers.read"
+	ScopeAuthorizationServersManage = "okta.authorizationServers.manage"
+	ScopeBehaviorsRead              = "okta.behaviors.read"
+	ScopeBehaviorsManage            = "okta.behaviors.manage"
+	ScopeRolesRead                  = "okta.roles.read"
+	ScopeRolesManage                = "okta.roles.manage"
+	ScopeEmailServersRead           = "okta.emailServers.read"
+	ScopeEmailServersManage         = "okta.emailServers.manage"
+	ScopeOrgsRead                   = "okta.orgs.read"
+	ScopeOrgsManage                 = "okta.orgs.manage"
+	ScopeAgentPoolsRead             = "okta.agentPools.read"
+	ScopeAgentPoolsManage           = "okta.agentPools.manage"
+	ScopeAuthenticatorsRead         = "okta.authenticators.read"
+	ScopeAuthenticatorsManage       = "okta.authenticators.manage"
+	ScopePoliciesRead               = "okta.policies.read"
+	ScopePoliciesManage             = "okta.policies.manage"
+	ScopePrincipalRateLimitsRead    = "okta.principalRateLimits.read"
+	ScopePrincipalRateLimitsManage  = "okta.principalRateLimits.manage"
+	ScopeIdPsRead                   = "okta.idps.read"
+	ScopeIdPsManage                 = "okta.idps.manage"
+	ScopeEmailDomainsRead           = "okta.emailDomains.read"
+	ScopeEmailDomainsManage         = "okta.emailDomains.manage"
+	ScopeCaptchasRead               = "okta.captchas.read"
+	ScopeCaptchasManage             = "okta.captchas.manage"
+)
+
+// endpointScopes maps API paths to the scopes needed to read and modify them.
+// The first entry matching a path wins, so more specific paths come first.
+var endpointScopes = []struct {
+	prefix   string
+	contains string
+	read     string
+	manage   string
+}{
+	{"/api/v1/users/", "/factors", ScopeFactorsRead, ScopeFactorsManage},
+	{"/api/v1/users/", "/groups", ScopeGroupsRead, ScopeGroupsManage},
+	{"/api/v1/users/", "/devices", ScopeDevicesRead, ScopeDevicesManage},
+	{"/api/v1/users/", "/roles", ScopeRolesRead, ScopeRolesManage},
+	{"/api/v1/users", "", ScopeUsersRead, ScopeUsersManage},
+	{"/api/v1/meta/types/user", "", ScopeUserTypesRead, ScopeUserTypesManage},
+	{"/api/v1/meta/schemas", "", ScopeSchemasRead, ScopeSchemasManage},
+	{"/api/v1/groups/", "/roles", ScopeRolesRead, ScopeRolesManage},
+	{"/api/v1/groups", "", ScopeGroupsRead, ScopeGroupsManage},
+	{"/api/v1/apps", "", ScopeAppsRead, ScopeAppsManage},
+	{"/api/v1/logs", "", ScopeLogsRead, ScopeLogsRead},
+	{"/api/v1/authorizationServers", "", ScopeAuthorizationServersRead, ScopeAuthorizationServersManage},
+	{"/api/v1/behaviors", "", ScopeBehaviorsRead, ScopeBehaviorsManage},
+	{"/api/v1/iam", "", ScopeRolesRead, ScopeRolesManage},
+	{"/api/v1/email-servers", "", ScopeEmailServersRead, ScopeEmailServersManage},
+	{"/api/v1/rate-limit-settings", "", ScopeOrgsRead, ScopeOrgsManage},
+	{"/api/v1/agentPools", "", ScopeAgentPoolsRead, ScopeAgentPoolsManage},
+	{"/api/v1/authenticators", "", ScopeAuthenticatorsRead, ScopeAuthenticatorsManage},
+	{"/api/v1/policies", "", ScopePoliciesRead, ScopePoliciesManage},
+	{"/api/v1/principal-rate-limits", "", ScopePrincipalRateLimitsRead, ScopePrincipalRateLimitsManage},
+	{"/api/v1/idps", "", ScopeIdPsRead, ScopeIdPsManage},
+	{"/api/v1/email-domains", "", ScopeEmailDomainsRead, ScopeEmailDomainsManage},
+	{"/api/v1/captchas", "", ScopeCaptchasRead, ScopeCaptchasManage},
+	{"/api/v1/org/captcha", "", ScopeCaptchasRead, ScopeCaptchasManage},
+}
+
+// RequiredScope returns the OAuth scope needed to call the API endpoint with
+// the given method and path, or an empty string if it isn't known.
+// A manage scope also grants the matching read access.
+func RequiredScope(method, path string) string {
+	read, manage := endpointScope(path)
+	if method == "GET" || method == "HEAD" {
+		return read
+	}
+	return manage
+}
+
+// endpointScope returns the read and manage scopes of the endpoint at path,
+// or empty strings if they aren't known.
+func endpointScope(path string) (read, manage string) {
+	for _, e := range endpointScopes {
+		if strings.HasPrefix(path, e.prefix) && strings.Contains(path, e.contains) {
+			return e.read, e.manage
+		}
+	}
+
+	return "", ""
+}
+
+// HasScopeFor reports whether the scopes given to NewWithOAuth grant the
+// scope that RequiredScope returns for the endpoint, so that a missing scope
+// can be caught, e.g. on startup, before the calls fail with a *ScopeError.
+// It is true for the endpoints whose scope isn't known, and for the clients
+// using an API token, which have the permissions of its admin.
+func (c *Client) HasScopeFor(method, path string) bool {
+	if c.oauth == nil {
+		return true
+	}
+
+	required := RequiredScope(method, path)
+	if required == "" {
+		return true
+	}
+	_, manage := endpointScope(path)
+
+	for _, scope := range c.oauth.scopes {
+		if scope == required || scope == manage {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeError is returned, before anything is sent, for a call of a client
+// created by NewWithOAuth without the scope the endpoint requires.
+type ScopeError struct {
+	Method string
+	Path   string
+	Scope  string // the missing scope, as returned by RequiredScope
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("okta: %v %v requires the OAuth scope %v", e.Method, e.Path, e.Scope)
+}
@@ -0,0 +1,39 @@
+package okta
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestMissingScopeIsNotSent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("sent %v %v", r.Method, r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewWithOAuth("client", key, []string{ScopeUsersRead}, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.BaseURL, _ = url.Parse(srv.URL)
+
+	_, err = c.call(context.Background(), "PUT", "/api/v1/users/00u1", &User{ID: "00u1"}, nil)
+	var scopeErr *ScopeError
+	if !errors.As(err, &scopeErr) {
+		t.Fatalf("err = %v, want a *ScopeError", err)
+	}
+	if scopeErr.Scope != ScopeUsersManage {
+		t.Errorf("missing scope = %q, want %q", scopeErr.Scope, ScopeUsersManage)
+	}
+}
@@ -0,0 +1,81 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// timestamp decodes the RFC3339 timestamps returned by Okta.
+// Okta sends null for timestamps that were never set (e.g. lastLogin for a
+// user who never signed in), and some endpoints send an empty string instead;
+// both decode to the zero time.Time.
+type timestamp time.Time
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == nil || *s == "" {
+		*t = timestamp{}
+		return nil
+	}
+
+	v, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return err
+	}
+
+	*t = timestamp(v)
+	return nil
+}
+
+// oktaTimeFormat is the timestamp format of Okta: UTC, with milliseconds.
+const oktaTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// OktaTime is a time sent to Okta, in request bodies and query parameters,
+// such as LogListOptions.Since. It is encoded in UTC with millisecond
+// precision, the form Okta accepts everywhere, and the zero time is encoded
+// as null, or left out of the query. The timestamps of the resources Okta
+// returns are time.Time.
+type OktaTime time.Time
+
+// Time returns t as a time.Time.
+func (t OktaTime) Time() time.Time {
+	return time.Time(t)
+}
+
+// IsZero reports whether t is the zero time.
+func (t OktaTime) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+// String returns t in the Okta format.
+func (t OktaTime) String() string {
+	return time.Time(t).UTC().Format(oktaTimeFormat)
+}
+
+// MarshalJSON encodes t as a string in the Okta format, or null if t is zero.
+func (t OktaTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes an RFC3339 timestamp, null or "" decoding to the zero time.
+func (t *OktaTime) UnmarshalJSON(data []byte) error {
+	return (*timestamp)(t).UnmarshalJSON(data)
+}
+
+// EncodeValues adds t to the query parameters v, in the Okta format,
+// unless t is zero.
+func (t OktaTime) EncodeValues(key string, v *url.Values) error {
+	if !t.IsZero() {
+		v.Set(key, t.String())
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package okta
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResourceTimestamps(t *testing.T) {
+	var idp IdentityProvider
+	err := json.Unmarshal([]byte(`{"id": "0oa1", "created": "", "lastUpdated": "2020-01-02T03:04:05.000Z"}`), &idp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !idp.Created.IsZero() {
+		t.Errorf("Created = %v, want the zero time", idp.Created)
+	}
+	if want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC); !idp.LastUpdated.Equal(want) {
+		t.Errorf("LastUpdated = %v, want %v", idp.LastUpdated, want)
+	}
+
+	data, err := json.Marshal(&idp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); strings.Contains(s, "created") || strings.Contains(s, "lastUpdated") {
+		t.Errorf("encoded %s, want no read-only timestamps", s)
+	}
+}
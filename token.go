@@ -0,0 +1,42 @@
+package okta
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RefreshToken is an OAuth refresh token issued to a user for a client,
+// as listed for the user or for the application.
+type RefreshToken struct {
+	ID          string          `json:"id"`
+	Status      string          `json:"status"`
+	Created     time.Time       `json:"created"`
+	LastUpdated time.Time       `json:"lastUpdated"`
+	ExpiresAt   time.Time       `json:"expiresAt"`
+	Issuer      string          `json:"issuer"`
+	ClientID    string          `json:"clientId"`
+	UserID      string          `json:"userId"`
+	Scopes      []string        `json:"scopes"`
+	Links       map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a refresh token, mapping null or empty timestamps to
+// the zero time.
+func (r *RefreshToken) UnmarshalJSON(data []byte) error {
+	type alias RefreshToken
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+		ExpiresAt   timestamp `json:"expiresAt"`
+	}{alias: (*alias)(r)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	r.Created = time.Time(aux.Created)
+	r.LastUpdated = time.Time(aux.LastUpdated)
+	r.ExpiresAt = time.Time(aux.ExpiresAt)
+	return nil
+}
@@ -0,0 +1,40 @@
+package okta
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Connection settings of WithTunedTransport.
+const (
+	tunedMaxConnsPerHost = 64
+	tunedIdleConnTimeout = 90 * time.Second
+	tunedKeepAlive       = 30 * time.Second
+)
+
+// WithTunedTransport makes the client use a transport tuned for sending many
+// concurrent requests to the single Okta host: up to 64 connections to it,
+// all kept idle for reuse up to 90 seconds, with TCP keep-alives every 30
+// seconds. http.DefaultTransport only keeps 2 idle connections per host, so
+// bulk operations keep opening new connections.
+func WithTunedTransport() Option {
+	return func(c *Client) {
+		c.client = &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   30 * time.Second,
+					KeepAlive: tunedKeepAlive,
+				}).DialContext,
+				ForceAttemptHTTP2:     true,
+				MaxIdleConns:          tunedMaxConnsPerHost,
+				MaxIdleConnsPerHost:   tunedMaxConnsPerHost,
+				MaxConnsPerHost:       tunedMaxConnsPerHost,
+				IdleConnTimeout:       tunedIdleConnTimeout,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ExpectContinueTimeout: time.Second,
+			},
+		}
+	}
+}
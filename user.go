@@ -2,61 +2,296 @@ package okta
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
+	"net/url"
+	"sync"
 	"time"
-
-	"github.com/tomnomnom/linkheader"
 )
 
 var (
 	ErrAuthenticationFailed = errors.New("authentication failed. Please check username/password.")
 )
 
+// User statuses, see https://developer.okta.com/docs/reference/api/users/#user-status.
+const (
+	UserStatusStaged          = "STAGED"
+	UserStatusProvisioned     = "PROVISIONED"
+	UserStatusActive          = "ACTIVE"
+	UserStatusRecovery        = "RECOVERY"
+	UserStatusPasswordExpired = "PASSWORD_EXPIRED"
+	UserStatusLockedOut       = "LOCKED_OUT"
+	UserStatusSuspended       = "SUSPENDED"
+	UserStatusDeprovisioned   = "DEPROVISIONED"
+)
+
 // UserService handles users operations.
 type UserService service
 
+func userURL(id string) string {
+	return fmt.Sprintf("/api/v1/users/%v", id)
+}
+
 // User represents a Okta user.
 type User struct {
-	ID              string            `json:"id"`
-	Status          string            `json:"status"`
-	LastLogin       string            `json:"last_login"`
-	Created         string            `json:"created"`
-	LastUpdated     string            `json:"last_updated"`
-	PasswordChanged string            `json:"password_changed"`
-	Profile         map[string]string `json:"profile"`
-}
-
-type getUsersQuery struct {
-	Limit int `url:"limit,omitempty"`
-}
-
-type authenticationResponse struct {
-	ExpiresAt    string `json:"expiresAt"`
-	Status       string `json:"status"`
-	RelayState   string `json:"relayState"`
-	SessionToken string `json:"sessionToken"`
-	Embedded     struct {
-		User struct {
-			ID              string    `json:"id"`
-			PasswordChanged time.Time `json:"passwordChanged"`
-			Profile         struct {
-				Login     string `json:"login"`
-				FirstName string `json:"firstName"`
-				LastName  string `json:"lastName"`
-				Locale    string `json:"locale"`
-				TimeZone  string `json:"timeZone"`
-			} `json:"profile"`
-		} `json:"user"`
+	ID              string    `json:"id"`
+	Status          string    `json:"status"`
+	Created         time.Time `json:"created"`
+	Activated       time.Time `json:"activated"`
+	StatusChanged   time.Time `json:"statusChanged"`
+	LastLogin       time.Time `json:"lastLogin"`
+	LastUpdated     time.Time `json:"lastUpdated"`
+	PasswordChanged time.Time `json:"passwordChanged"`
+
+	// Profile holds every profile attribute, including the custom attributes
+	// of the organisation, as decoded by encoding/json, except for numbers,
+	// which are json.Number so that large integers keep their precision.
+	// Sending it back unchanged preserves the attributes the caller doesn't
+	// know about.
+	Profile map[string]interface{} `json:"profile"`
+
+	// Links are the operations currently allowed on the user, keyed by name.
+	Links map[string]Link `json:"_links"`
+
+	// Embedded holds the sub-resources embedded by an expand parameter, such
+	// as the user type, keyed by name and left undecoded.
+	Embedded map[string]json.RawMessage `json:"_embedded,omitempty"`
+}
+
+// UnmarshalJSON decodes a user, mapping null or empty timestamps to the zero time.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type alias User
+	aux := &struct {
+		*alias
+		Created         timestamp `json:"created"`
+		Activated       timestamp `json:"activated"`
+		StatusChanged   timestamp `json:"statusChanged"`
+		LastLogin       timestamp `json:"lastLogin"`
+		LastUpdated     timestamp `json:"lastUpdated"`
+		PasswordChanged timestamp `json:"passwordChanged"`
+	}{alias: (*alias)(u)}
+
+	if err := unmarshalNumbers(data, aux); err != nil {
+		return err
+	}
+
+	u.Created = time.Time(aux.Created)
+	u.Activated = time.Time(aux.Activated)
+	u.StatusChanged = time.Time(aux.StatusChanged)
+	u.LastLogin = time.Time(aux.LastLogin)
+	u.LastUpdated = time.Time(aux.LastUpdated)
+	u.PasswordChanged = time.Time(aux.PasswordChanged)
+	return nil
+}
+
+// HasLoggedIn reports whether the user ever logged in, LastLogin being the
+// zero time otherwise.
+func (u *User) HasLoggedIn() bool {
+	return !u.LastLogin.IsZero()
+}
+
+// GetString returns the profile attribute attr, and whether it is set
+// to a string.
+func (u *User) GetString(attr string) (string, bool) {
+	v, ok := u.Profile[attr].(string)
+	return v, ok
+}
+
+// Login returns the login of the user, or an empty string.
+func (u *User) Login() string {
+	v, _ := u.GetString("login")
+	return v
+}
+
+// Email returns the primary email of the user, or an empty string.
+func (u *User) Email() string {
+	v, _ := u.GetString("email")
+	return v
+}
+
+// FirstName returns the first name of the user, or an empty string.
+func (u *User) FirstName() string {
+	v, _ := u.GetString("firstName")
+	return v
+}
+
+// LastName returns the last name of the user, or an empty string.
+func (u *User) LastName() string {
+	v, _ := u.GetString("lastName")
+	return v
+}
+
+// CreateUserRequest describes a user to create.
+type CreateUserRequest struct {
+	Profile     map[string]interface{} `json:"profile"`
+	Credentials *UserCredentials       `json:"credentials,omitempty"`
+	GroupIDs    []string               `json:"groupIds,omitempty"`
+
+	// Type is the user type of the new user. The default user type is used when nil.
+	Type *UserTypeRef `json:"type,omitempty"`
+}
+
+// UserTypeRef references a UserType by ID.
+type UserTypeRef struct {
+	ID string `json:"id"`
+}
+
+// UserCredentials are the credentials of a user.
+type UserCredentials struct {
+	Password         *PasswordCredential         `json:"password,omitempty"`
+	RecoveryQuestion *RecoveryQuestionCredential `json:"recovery_question,omitempty"`
+}
+
+// PasswordCredential is the password of a user. Okta never returns its value.
+type PasswordCredential struct {
+	Value string `json:"value,omitempty"`
+}
+
+// RecoveryQuestionCredential is the recovery question of a user.
+// Okta never returns the answer.
+type RecoveryQuestionCredential struct {
+	Question string `json:"question,omitempty"`
+	Answer   string `json:"answer,omitempty"`
+}
+
+// The fields of the queries below are sent even when false, unlike the
+// omitempty fields of the option structs: Okta activates new users by
+// default, so activate=false must be explicit.
+type createUserQuery struct {
+	Activate bool `url:"activate"`
+}
+
+type sendEmailQuery struct {
+	SendEmail bool `url:"sendEmail"`
+}
+
+// ActivationResponse is returned by lifecycle operations that issue an activation token.
+// ActivationURL and ActivationToken are only set when no email was sent.
+type ActivationResponse struct {
+	ActivationURL   string `json:"activationUrl"`
+	ActivationToken string `json:"activationToken"`
+}
+
+// AuthnResponse is the transaction returned by the authentication API.
+// Status tells which step of the transaction is expected next; see
+// https://developer.okta.com/docs/reference/api/authn/#transaction-state.
+type AuthnResponse struct {
+	ExpiresAt     time.Time `json:"expiresAt"`
+	Status        string    `json:"status"`
+	RelayState    string    `json:"relayState"`
+	StateToken    string    `json:"stateToken"`
+	SessionToken  string    `json:"sessionToken"`
+	RecoveryToken string    `json:"recoveryToken"`
+	RecoveryType  string    `json:"recoveryType"`
+	FactorType    string    `json:"factorType"`
+	FactorResult  string    `json:"factorResult"`
+	Embedded      struct {
+		User AuthnUser `json:"user"`
 	} `json:"_embedded"`
 }
 
+// UnmarshalJSON decodes a transaction, mapping a null or empty timestamp to
+// the zero time.
+func (a *AuthnResponse) UnmarshalJSON(data []byte) error {
+	type alias AuthnResponse
+	aux := &struct {
+		*alias
+		ExpiresAt timestamp `json:"expiresAt"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.ExpiresAt = time.Time(aux.ExpiresAt)
+	return nil
+}
+
+// AuthnUser is the user of an authentication transaction.
+type AuthnUser struct {
+	ID              string    `json:"id"`
+	PasswordChanged time.Time `json:"passwordChanged"`
+	Profile         struct {
+		Login     string `json:"login"`
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+		Locale    string `json:"locale"`
+		TimeZone  string `json:"timeZone"`
+	} `json:"profile"`
+}
+
+// UnmarshalJSON decodes a user, mapping a null or empty timestamp to the
+// zero time.
+func (a *AuthnUser) UnmarshalJSON(data []byte) error {
+	type alias AuthnUser
+	aux := &struct {
+		*alias
+		PasswordChanged timestamp `json:"passwordChanged"`
+	}{alias: (*alias)(a)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	a.PasswordChanged = time.Time(aux.PasswordChanged)
+	return nil
+}
+
+// RecoveryResponse is returned when starting a password recovery.
+// ResetPasswordURL is only set when no email was sent.
+type RecoveryResponse struct {
+	ResetPasswordURL string `json:"resetPasswordUrl"`
+}
+
+// Authentication transaction statuses, see AuthnResponse.
+const (
+	AuthnStatusSuccess         = "SUCCESS"
+	AuthnStatusMFARequired     = "MFA_REQUIRED"
+	AuthnStatusMFAEnroll       = "MFA_ENROLL"
+	AuthnStatusMFAChallenge    = "MFA_CHALLENGE"
+	AuthnStatusPasswordExpired = "PASSWORD_EXPIRED"
+	AuthnStatusLockedOut       = "LOCKED_OUT"
+)
+
+// IsSuccess reports whether the user is authenticated, SessionToken being set.
+func (r *AuthnResponse) IsSuccess() bool {
+	return r.Status == AuthnStatusSuccess
+}
+
+// IsMFARequired reports whether the user must verify one of its factors
+// to complete the authentication.
+func (r *AuthnResponse) IsMFARequired() bool {
+	return r.Status == AuthnStatusMFARequired
+}
+
+// IsMFAChallenge reports whether a factor verification is in progress,
+// such as a pending push notification.
+func (r *AuthnResponse) IsMFAChallenge() bool {
+	return r.Status == AuthnStatusMFAChallenge
+}
+
 // Authenticate the user with username and password.
 // relayState can be used to add additional information.
+// Any transaction status but SUCCESS, including the MFA ones, fails with
+// ErrAuthenticationFailed; use Authn to continue those transactions.
 func (s *UserService) Authenticate(ctx context.Context, username, password, relayState string) (*User, error) {
-	u := "/api/v1/authn"
+	transaction, _, err := s.Authn(ctx, username, password, relayState)
+	if err != nil {
+		return nil, err
+	}
 
+	if !transaction.IsSuccess() {
+		return nil, ErrAuthenticationFailed
+	}
+
+	return s.GetUser(ctx, transaction.Embedded.User.ID)
+}
+
+// Authn starts the authentication of the user with username and password,
+// and returns the transaction whatever its status. A wrong password fails
+// with an *AuthenticationError; the MFA statuses are returned as a transaction.
+func (s *UserService) Authn(ctx context.Context, username, password, relayState string) (*AuthnResponse, *Response, error) {
 	post := struct {
 		Username   string                 `json:"username"`
 		Password   string                 `json:"password"`
@@ -71,22 +306,8 @@ func (s *UserService) Authenticate(ctx context.Context, username, password, rela
 			"multiOptionalFactorEnroll": false,
 		},
 	}
-	req, err := s.client.NewRequest("POST", u, post)
-	if err != nil {
-		return nil, err
-	}
 
-	var resp authenticationResponse
-	_, err = s.client.Do(ctx, req, &resp)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.Status != "SUCCESS" {
-		return nil, ErrAuthenticationFailed
-	}
-
-	return s.GetUser(ctx, resp.Embedded.User.ID)
+	return s.authn(ctx, "/api/v1/authn", post)
 }
 
 // GetUsersOptions allows to specify query options.
@@ -101,7 +322,8 @@ type GetUsersOptions struct {
 	Pages int
 }
 
-// GetUsers returns all the users.
+// GetUsers returns all the users. On error, including the cancellation of
+// ctx, the users fetched so far are returned along with it.
 func (s *UserService) GetUsers(ctx context.Context, options *GetUsersOptions) ([]*User, error) {
 	u := "/api/v1/users"
 
@@ -110,7 +332,7 @@ func (s *UserService) GetUsers(ctx context.Context, options *GetUsersOptions) ([
 		perPage = 200
 	}
 
-	uu, err := addOptions(u, &getUsersQuery{Limit: perPage})
+	uu, err := addOptions(u, &ListOptions{Limit: perPage})
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +343,7 @@ LOOP:
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("context done")
+			return users, ctx.Err()
 		default:
 			req, err := s.client.NewRequest("GET", uu, nil)
 			if err != nil {
@@ -135,18 +357,12 @@ LOOP:
 			var usersBatch []*User
 			resp, err := s.client.Do(ctx, req, &usersBatch)
 			if err != nil {
-				return nil, err
+				return users, err
 			}
 			users = append(users, usersBatch...)
-
-			// Okta returns the next URL to page in the Link header,
-			// see https://developer.okta.com/docs/reference/api-overview/#link-header.
-			links := linkheader.Parse(strings.Join(resp.Header["Link"], ","))
-			var next string
-			for _, link := range links {
-				if link.Rel == "next" {
-					next = link.URL
-				}
+			next, err := s.client.nextPage(resp)
+			if err != nil {
+				return users, err
 			}
 
 			index++
@@ -163,8 +379,122 @@ LOOP:
 	return users, nil
 }
 
-// GetUser returns a user.
+// UserListOptions allows to filter the users returned by List.
+type UserListOptions struct {
+	// Q matches users whose first name, last name or email starts with Q.
+	Q string `url:"q,omitempty"`
+
+	// Filter is a filter expression on a limited set of user properties,
+	// for example `status eq "ACTIVE"`.
+	Filter string `url:"filter,omitempty"`
+
+	// Search is a search expression on any user property,
+	// for example `profile.department eq "Engineering"`.
+	Search string `url:"search,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of the users matching opt. To list the next page, set
+// opt.After to the NextCursor of the returned *Response, until it is empty.
+func (s *UserService) List(ctx context.Context, opt *UserListOptions) ([]*User, *Response, error) {
+	u, err := addOptions("/api/v1/users", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var users []*User
+	resp, err := s.client.call(ctx, "GET", u, nil, &users)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return users, resp, nil
+}
+
+// Pages calls fn with each page of the users matching opt, following the
+// NextCursor of the responses, from opt.After if set. opt isn't modified.
+// It stops at the first error of fn, which it returns, or when ctx is done.
+func (s *UserService) Pages(ctx context.Context, opt *UserListOptions, fn func(page []*User) error) error {
+	var o UserListOptions
+	if opt != nil {
+		o = *opt
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, resp, err := s.List(ctx, &o)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if o.After = resp.NextCursor; o.After == "" {
+			return nil
+		}
+	}
+}
+
+// ListAll returns all the users matching opt, listing them page after page
+// with Pages. On error, including the cancellation of ctx, the users listed
+// so far are returned along with it.
+func (s *UserService) ListAll(ctx context.Context, opt *UserListOptions) ([]*User, error) {
+	var users []*User
+	err := s.Pages(ctx, opt, func(page []*User) error {
+		users = append(users, page...)
+		return nil
+	})
+
+	return users, err
+}
+
+// Create creates a user. If activate is true, the user is activated
+// and Okta sends them an activation email when they have no password.
+func (s *UserService) Create(ctx context.Context, user *CreateUserRequest, activate bool) (*User, *Response, error) {
+	u, err := addOptions("/api/v1/users", &createUserQuery{Activate: activate})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created User
+	resp, err := s.client.call(ctx, "POST", u, user, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// GetUser returns a user, from the UserCache of the client if it has one.
+// Users are only cached by ID, the key evicted when they change, so looking
+// a user up by login always fetches it.
 func (s *UserService) GetUser(ctx context.Context, id string) (*User, error) {
+	if s.client.UserCache != nil {
+		if user, ok := s.client.UserCache.Get(id); ok {
+			return user, nil
+		}
+	}
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.client.UserCache != nil {
+		s.client.UserCache.Set(user.ID, user)
+	}
+
+	return user, nil
+}
+
+// getUser fetches a user from Okta, bypassing the UserCache, for the helpers
+// which must see the current status of the user.
+func (s *UserService) getUser(ctx context.Context, id string) (*User, error) {
 	u := fmt.Sprintf("/api/v1/users/%v", id)
 
 	var user User
@@ -186,8 +516,60 @@ func (s *UserService) GetUser(ctx context.Context, id string) (*User, error) {
 	return &user, nil
 }
 
+// evict removes the user id from the UserCache of the client, if it has
+// one, as a change made to the user makes the cached copy stale.
+func (s *UserService) evict(id string) {
+	if s.client.UserCache != nil {
+		s.client.UserCache.Delete(id)
+	}
+}
+
+// ResolveID returns the ID of the user whose login or ID is loginOrID, for
+// operations which must key on IDs, since logins can change. Okta looks users
+// up by either, so it takes a single call, or none when loginOrID is an ID
+// and the client has a UserCache which already holds the user.
+func (s *UserService) ResolveID(ctx context.Context, loginOrID string) (string, error) {
+	user, err := s.GetUser(ctx, url.PathEscape(loginOrID))
+	if err != nil {
+		return "", err
+	}
+
+	return user.ID, nil
+}
+
+// GetMany fetches the users of userIDs, with at most concurrency requests at
+// once, and returns them by ID along with the error of each ID which could not
+// be fetched. Once ctx is done the IDs not fetched yet fail with ctx.Err().
+// Rate limited requests are only retried when the client has MaxRetries set.
+func (s *UserService) GetMany(ctx context.Context, userIDs []string, concurrency int) (map[string]*User, map[string]error) {
+	var mu sync.Mutex
+	users := make(map[string]*User, len(userIDs))
+	errs := make(map[string]error)
+	forEach(ctx, len(userIDs), concurrency, func(i int) {
+		user, err := s.GetUser(ctx, userIDs[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[userIDs[i]] = err
+		} else {
+			users[userIDs[i]] = user
+		}
+	})
+
+	for _, id := range userIDs {
+		if users[id] == nil && errs[id] == nil {
+			errs[id] = ctx.Err()
+		}
+	}
+
+	return users, errs
+}
+
 // UpdateCustomAttributes returns a user.
 func (s *UserService) UpdateCustomAttributes(ctx context.Context, id string, attributes map[string]string) error {
+	defer s.evict(id)
+
 	u := fmt.Sprintf("/api/v1/users/%v", id)
 
 	post := struct {
@@ -211,3 +593,504 @@ func (s *UserService) UpdateCustomAttributes(ctx context.Context, id string, att
 
 	return nil
 }
+
+// PartialUpdate updates the profile attributes of a user set in profile,
+// leaving the others untouched, and returns the updated user. An attribute
+// set to nil is sent as null, which clears it: this is the only way to
+// unset an attribute.
+func (s *UserService) PartialUpdate(ctx context.Context, userID string, profile map[string]interface{}) (*User, *Response, error) {
+	defer s.evict(userID)
+
+	u := fmt.Sprintf("/api/v1/users/%v", userID)
+
+	post := struct {
+		Profile map[string]interface{} `json:"profile"`
+	}{
+		profile,
+	}
+
+	var user User
+	resp, err := s.client.call(ctx, "POST", u, post, &user)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &user, resp, nil
+}
+
+// Reactivate reactivates a user in PROVISIONED status, i.e. a user who has not
+// completed their activation yet, and issues a new activation token.
+// If sendEmail is true, Okta re-sends the activation email to the user.
+func (s *UserService) Reactivate(ctx context.Context, userID string, sendEmail bool) (*ActivationResponse, *Response, error) {
+	defer s.evict(userID)
+
+	var activation ActivationResponse
+	resp, err := s.client.lifecycle(ctx, userURL(userID), "reactivate", &sendEmailQuery{SendEmail: sendEmail}, &activation)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &activation, resp, nil
+}
+
+// ResendActivation sends the activation email to a user who has not completed
+// their activation yet. Okta refuses it with an *ErrorResponse when the user
+// isn't in PROVISIONED status.
+func (s *UserService) ResendActivation(ctx context.Context, userID string) (*Response, error) {
+	_, resp, err := s.Reactivate(ctx, userID, true)
+	return resp, err
+}
+
+// ForgotPassword starts a password recovery for the user and returns the
+// one-time reset password URL. If sendEmail is true, Okta emails the link to the
+// user instead and ResetPasswordURL is empty.
+func (s *UserService) ForgotPassword(ctx context.Context, userID string, sendEmail bool) (*RecoveryResponse, *Response, error) {
+	defer s.evict(userID)
+
+	u := fmt.Sprintf("/api/v1/users/%v/credentials/forgot_password", userID)
+
+	uu, err := addOptions(u, &sendEmailQuery{SendEmail: sendEmail})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recovery RecoveryResponse
+	resp, err := s.client.call(ctx, "POST", uu, nil, &recovery)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &recovery, resp, nil
+}
+
+// ChangeRecoveryQuestion replaces the recovery question of a user, after
+// checking its current password. Okta returns the updated credentials rather
+// than the user; a wrong password fails with a 403 *ErrorResponse.
+func (s *UserService) ChangeRecoveryQuestion(ctx context.Context, userID, password, question, answer string) (*UserCredentials, *Response, error) {
+	defer s.evict(userID)
+
+	u := fmt.Sprintf("/api/v1/users/%v/credentials/change_recovery_question", userID)
+
+	post := UserCredentials{
+		Password:         &PasswordCredential{Value: password},
+		RecoveryQuestion: &RecoveryQuestionCredential{Question: question, Answer: answer},
+	}
+
+	var credentials UserCredentials
+	resp, err := s.client.call(ctx, "POST", u, post, &credentials)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &credentials, resp, nil
+}
+
+// SetPassword sets the password of a user as an admin, without the current
+// one. A password breaking the password policy of the user fails with an
+// *ErrorResponse whose ErrorCauses name the broken rules; the policy can be
+// read beforehand with PolicyService.List and PolicyTypePassword.
+func (s *UserService) SetPassword(ctx context.Context, userID, password string) (*User, *Response, error) {
+	defer s.evict(userID)
+
+	post := struct {
+		Credentials UserCredentials `json:"credentials"`
+	}{
+		UserCredentials{Password: &PasswordCredential{Value: password}},
+	}
+
+	var user User
+	resp, err := s.client.call(ctx, "POST", fmt.Sprintf("/api/v1/users/%v", userID), post, &user)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &user, resp, nil
+}
+
+// RecoverPassword starts a self-service password recovery transaction for
+// username, sending the recovery token through factorType ("EMAIL", "SMS" or "CALL").
+// The token received by the user is then checked with VerifyRecoveryToken.
+func (s *UserService) RecoverPassword(ctx context.Context, username, factorType, relayState string) (*AuthnResponse, *Response, error) {
+	post := struct {
+		Username   string `json:"username"`
+		FactorType string `json:"factorType"`
+		RelayState string `json:"relayState,omitempty"`
+	}{
+		username,
+		factorType,
+		relayState,
+	}
+
+	return s.authn(ctx, "/api/v1/authn/recovery/password", post)
+}
+
+// VerifyRecoveryToken verifies a recovery token and returns the recovery
+// transaction, which usually continues with the RECOVERY status.
+func (s *UserService) VerifyRecoveryToken(ctx context.Context, recoveryToken string) (*AuthnResponse, *Response, error) {
+	post := struct {
+		RecoveryToken string `json:"recoveryToken"`
+	}{
+		recoveryToken,
+	}
+
+	return s.authn(ctx, "/api/v1/authn/recovery/token", post)
+}
+
+// authn sends an unauthenticated request to the authentication API.
+func (s *UserService) authn(ctx context.Context, u string, body interface{}) (*AuthnResponse, *Response, error) {
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var transaction AuthnResponse
+	resp, err := s.client.Do(ctx, req, &transaction)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &transaction, resp, nil
+}
+
+// Deactivate deactivates a user, moving it to the DEPROVISIONED status.
+// If sendEmail is true, Okta notifies the admin.
+func (s *UserService) Deactivate(ctx context.Context, userID string, sendEmail bool) (*Response, error) {
+	defer s.evict(userID)
+
+	return s.client.lifecycle(ctx, userURL(userID), "deactivate", &sendEmailQuery{SendEmail: sendEmail}, nil)
+}
+
+// Delete deletes a user. Okta only deletes users in DEPROVISIONED status:
+// calling Delete on any other user deactivates it instead.
+// If sendEmail is true, Okta notifies the admin.
+func (s *UserService) Delete(ctx context.Context, userID string, sendEmail bool) (*Response, error) {
+	defer s.evict(userID)
+
+	u := fmt.Sprintf("/api/v1/users/%v", userID)
+
+	uu, err := addOptions(u, &sendEmailQuery{SendEmail: sendEmail})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.call(ctx, "DELETE", uu, nil, nil)
+}
+
+// statusPollInterval is how often WaitForStatus fetches the user.
+const statusPollInterval = time.Second
+
+// offboardTimeout is how long Offboard waits for a deactivation to show.
+const offboardTimeout = 30 * time.Second
+
+// Offboard deactivates a user, if it isn't already, waits up to 30 seconds
+// for the deactivation to complete, and then deletes the user.
+func (s *UserService) Offboard(ctx context.Context, userID string, sendEmail bool) (*Response, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != UserStatusDeprovisioned {
+		if resp, err := s.Deactivate(ctx, userID, sendEmail); err != nil {
+			return resp, fmt.Errorf("deactivate user %v: %w", userID, err)
+		}
+
+		if _, err := s.WaitForStatus(ctx, userID, UserStatusDeprovisioned, offboardTimeout); err != nil {
+			return nil, fmt.Errorf("wait for user %v deactivation: %w", userID, err)
+		}
+	}
+
+	resp, err := s.Delete(ctx, userID, sendEmail)
+	if err != nil {
+		return resp, fmt.Errorf("delete user %v: %w", userID, err)
+	}
+
+	return resp, nil
+}
+
+// Suspend suspends an ACTIVE user.
+// The status change may not be visible right away, see WaitForStatus.
+func (s *UserService) Suspend(ctx context.Context, userID string) (*Response, error) {
+	defer s.evict(userID)
+
+	return s.client.lifecycle(ctx, userURL(userID), "suspend", nil, nil)
+}
+
+// Unsuspend moves a SUSPENDED user back to ACTIVE.
+// The status change may not be visible right away, see WaitForStatus.
+func (s *UserService) Unsuspend(ctx context.Context, userID string) (*Response, error) {
+	defer s.evict(userID)
+
+	return s.client.lifecycle(ctx, userURL(userID), "unsuspend", nil, nil)
+}
+
+// WaitForStatus polls a user until it has the given status, which is useful
+// after a lifecycle operation to avoid acting on a stale status. It gives up
+// after timeout, or never if timeout is 0.
+func (s *UserService) WaitForStatus(ctx context.Context, userID, status string, timeout time.Duration) (*User, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for {
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if user.Status == status {
+			return user, nil
+		}
+
+		if err := sleep(ctx, statusPollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// DeprovisionInactive deactivates, with at most concurrency requests at once,
+// the users whose last login is before the given time, and returns them.
+// If dryRun is true, the users are only returned. Users who never logged in
+// are left alone, as are the already deprovisioned ones.
+// On error, the users deactivated so far are returned along with it.
+func (s *UserService) DeprovisionInactive(ctx context.Context, before time.Time, dryRun bool, concurrency int) ([]*User, error) {
+	opt := &UserListOptions{
+		Search:      "lastLogin lt " + quoteFilterTime(before),
+		ListOptions: ListOptions{Limit: 200},
+	}
+
+	var inactive []*User
+	for {
+		page, resp, err := s.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range page {
+			if u.Status != UserStatusDeprovisioned {
+				inactive = append(inactive, u)
+			}
+		}
+
+		if opt.After = cursorOf(resp); opt.After == "" {
+			break
+		}
+	}
+
+	if dryRun {
+		return inactive, nil
+	}
+
+	var mu sync.Mutex
+	var deactivated []*User
+	var err error
+	forEach(ctx, len(inactive), concurrency, func(i int) {
+		_, derr := s.Deactivate(ctx, inactive[i].ID, false)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if derr != nil {
+			if err == nil {
+				err = fmt.Errorf("deactivate user %v: %w", inactive[i].ID, derr)
+			}
+			return
+		}
+
+		deactivated = append(deactivated, inactive[i])
+	})
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return deactivated, err
+}
+
+// ListDevices returns the devices enrolled by a user.
+func (s *UserService) ListDevices(ctx context.Context, userID string) ([]*Device, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/devices", userID)
+
+	var userDevices []struct {
+		Device *Device `json:"device"`
+	}
+	resp, err := s.client.call(ctx, "GET", u, nil, &userDevices)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	devices := make([]*Device, 0, len(userDevices))
+	for _, d := range userDevices {
+		devices = append(devices, d.Device)
+	}
+
+	return devices, resp, nil
+}
+
+// ListRefreshTokensForClient returns the refresh tokens issued to a user for
+// the OAuth client clientID.
+func (s *UserService) ListRefreshTokensForClient(ctx context.Context, userID, clientID string) ([]*RefreshToken, *Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/clients/%v/tokens", userID, clientID)
+
+	var tokens []*RefreshToken
+	resp, err := s.client.call(ctx, "GET", u, nil, &tokens)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return tokens, resp, nil
+}
+
+// RevokeTokensForClient revokes every refresh token issued to a user for the
+// OAuth client clientID, leaving the user's other clients signed in.
+func (s *UserService) RevokeTokensForClient(ctx context.Context, userID, clientID string) (*Response, error) {
+	u := fmt.Sprintf("/api/v1/users/%v/clients/%v/tokens", userID, clientID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
+
+// AddToGroups adds a user to each of groupIDs, with at most concurrency
+// requests at once, and returns the IDs of the groups it could not be added
+// to, in order, along with the first error. Once ctx is done the groups not
+// handled yet are reported as failed. Rate limited requests are only retried
+// when the client has MaxRetries set.
+func (s *UserService) AddToGroups(ctx context.Context, userID string, groupIDs []string, concurrency int) ([]string, error) {
+	var mu sync.Mutex
+	added := make([]bool, len(groupIDs))
+	var err error
+	forEach(ctx, len(groupIDs), concurrency, func(i int) {
+		_, aerr := s.client.Group.AddUser(ctx, groupIDs[i], userID)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if aerr != nil {
+			if err == nil {
+				err = fmt.Errorf("add user %v to group %v: %w", userID, groupIDs[i], aerr)
+			}
+			return
+		}
+
+		added[i] = true
+	})
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	var failed []string
+	for i, id := range groupIDs {
+		if !added[i] {
+			failed = append(failed, id)
+		}
+	}
+
+	return failed, err
+}
+
+// ChangeType moves a user to the user type newTypeID, which is first checked
+// to exist. Okta only changes the type with a full replacement of the user,
+// so its current profile is fetched and sent back along with the new type;
+// the profile must be valid for the new type.
+func (s *UserService) ChangeType(ctx context.Context, userID, newTypeID string) (*Response, error) {
+	defer s.evict(userID)
+
+	if _, resp, err := s.client.UserType.Get(ctx, newTypeID); err != nil {
+		return resp, fmt.Errorf("get user type %v: %w", newTypeID, err)
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	put := struct {
+		Profile map[string]interface{} `json:"profile"`
+		Type    UserTypeRef            `json:"type"`
+	}{
+		user.Profile,
+		UserTypeRef{ID: newTypeID},
+	}
+
+	return s.client.call(ctx, "PUT", fmt.Sprintf("/api/v1/users/%v", userID), put, nil)
+}
+
+// DeactivateMany deactivates each of userIDs, with at most concurrency
+// requests at once, and returns the error of each user who could not be
+// deactivated. onProgress, if not nil, is called after each user with the
+// number of users done so far, failed ones included; calls are serialized.
+// Once ctx is done the users not handled yet fail with ctx.Err(), which is
+// also returned. Rate limited requests are only retried when the client has
+// MaxRetries set.
+func (s *UserService) DeactivateMany(ctx context.Context, userIDs []string, concurrency int, onProgress func(done, total int)) (map[string]error, error) {
+	var mu sync.Mutex
+	handled := make([]bool, len(userIDs))
+	errs := make(map[string]error)
+	done := 0
+	forEach(ctx, len(userIDs), concurrency, func(i int) {
+		_, err := s.Deactivate(ctx, userIDs[i], false)
+
+		mu.Lock()
+		defer mu.Unlock()
+		handled[i] = true
+		if err != nil {
+			errs[userIDs[i]] = err
+		}
+
+		done++
+		if onProgress != nil {
+			onProgress(done, len(userIDs))
+		}
+	})
+
+	for i, id := range userIDs {
+		if !handled[i] {
+			errs[id] = ctx.Err()
+		}
+	}
+
+	return errs, ctx.Err()
+}
+
+// FindByAttribute returns the users whose profile attribute attr, such as
+// "employeeNumber", equals value. The returned *Response is the one of the
+// last page of results. attr must be the variable name of an attribute,
+// made of letters, digits and underscores. On error, the users found so far
+// are returned along with it.
+func (s *UserService) FindByAttribute(ctx context.Context, attr, value string) ([]*User, *Response, error) {
+	if !isAttributeName(attr) {
+		return nil, nil, fmt.Errorf("okta: invalid profile attribute name %q", attr)
+	}
+
+	opt := &UserListOptions{
+		Search:      "profile." + attr + " eq " + quoteFilterValue(value),
+		ListOptions: ListOptions{Limit: 200},
+	}
+
+	var users []*User
+	for {
+		page, resp, err := s.List(ctx, opt)
+		if err != nil {
+			return users, resp, err
+		}
+
+		users = append(users, page...)
+		if opt.After = cursorOf(resp); opt.After == "" {
+			return users, resp, nil
+		}
+	}
+}
+
+// isAttributeName reports whether name is a valid profile attribute variable
+// name, and so can't change the meaning of the expression it is put in.
+func isAttributeName(name string) bool {
+	for i, r := range name {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '_':
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,83 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPartialUpdateSendsNull(t *testing.T) {
+	c, mux := setup(t)
+
+	var body []byte
+	mux.HandleFunc("/api/v1/users/00u1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %v, want POST", r.Method)
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"id": "00u1"}`))
+	})
+
+	_, _, err := c.User.PartialUpdate(context.Background(), "00u1", map[string]interface{}{
+		"nickName": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []byte(`{"profile":{"nickName":null}}`); !bytes.Equal(bytes.TrimSpace(body), want) {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
+
+func TestChangeTypeSendsCurrentProfile(t *testing.T) {
+	c, mux := setup(t)
+	c.UserCache = NewUserCache(time.Hour)
+	c.UserCache.Set("00u1", &User{ID: "00u1", Profile: map[string]interface{}{"nickName": "old"}})
+
+	mux.HandleFunc("/api/v1/meta/types/user/oty1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "oty1"}`))
+	})
+	var body []byte
+	mux.HandleFunc("/api/v1/users/00u1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			body, _ = ioutil.ReadAll(r.Body)
+		}
+		w.Write([]byte(`{"id": "00u1", "profile": {"nickName": "new"}}`))
+	})
+
+	if _, err := c.User.ChangeType(context.Background(), "00u1", "oty1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []byte(`{"profile":{"nickName":"new"},"type":{"id":"oty1"}}`); !bytes.Equal(bytes.TrimSpace(body), want) {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
+
+func TestResolveIDCachesByID(t *testing.T) {
+	c, mux := setup(t)
+	c.UserCache = NewUserCache(time.Hour)
+
+	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "00u1", "profile": {"login": "jane@example.com"}}`))
+	})
+
+	id, err := c.User.ResolveID(context.Background(), "jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "00u1" {
+		t.Errorf("ResolveID = %q, want 00u1", id)
+	}
+
+	if _, ok := c.UserCache.Get("jane@example.com"); ok {
+		t.Error("user cached by login, which evicting its ID doesn't clear")
+	}
+	if _, ok := c.UserCache.Get("00u1"); !ok {
+		t.Error("user not cached by ID")
+	}
+}
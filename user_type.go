@@ -0,0 +1,123 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UserTypeService manages custom user types.
+type UserTypeService service
+
+// UserType is a type of user, defining the schema of its profile.
+type UserType struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+	Description string    `json:"description"`
+	Default     bool      `json:"default"`
+	Created     time.Time `json:"created"`
+	CreatedBy   string    `json:"createdBy"`
+	LastUpdated time.Time `json:"lastUpdated"`
+
+	Links map[string]Link `json:"_links"`
+}
+
+// UnmarshalJSON decodes a user type, mapping null or empty timestamps to the
+// zero time.
+func (u *UserType) UnmarshalJSON(data []byte) error {
+	type alias UserType
+	aux := &struct {
+		*alias
+		Created     timestamp `json:"created"`
+		LastUpdated timestamp `json:"lastUpdated"`
+	}{alias: (*alias)(u)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	u.Created = time.Time(aux.Created)
+	u.LastUpdated = time.Time(aux.LastUpdated)
+	return nil
+}
+
+// userTypeBody holds the writable fields of a UserType.
+type userTypeBody struct {
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func newUserTypeBody(t *UserType) *userTypeBody {
+	return &userTypeBody{
+		Name:        t.Name,
+		DisplayName: t.DisplayName,
+		Description: t.Description,
+	}
+}
+
+// List returns all the user types of the organisation.
+func (s *UserTypeService) List(ctx context.Context) ([]*UserType, *Response, error) {
+	var types []*UserType
+	resp, err := s.client.call(ctx, "GET", "/api/v1/meta/types/user", nil, &types)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return types, resp, nil
+}
+
+// Get returns a user type.
+func (s *UserTypeService) Get(ctx context.Context, typeID string) (*UserType, *Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/types/user/%v", typeID)
+
+	var t UserType
+	resp, err := s.client.call(ctx, "GET", u, nil, &t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &t, resp, nil
+}
+
+// Create creates a user type. Name, DisplayName and Description are required.
+func (s *UserTypeService) Create(ctx context.Context, t *UserType) (*UserType, *Response, error) {
+	var created UserType
+	resp, err := s.client.call(ctx, "POST", "/api/v1/meta/types/user", newUserTypeBody(t), &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &created, resp, nil
+}
+
+// Update replaces a user type. The name of a user type can't be changed.
+func (s *UserTypeService) Update(ctx context.Context, typeID string, t *UserType) (*UserType, *Response, error) {
+	return s.update(ctx, "PUT", typeID, t)
+}
+
+// PartialUpdate updates the non-empty DisplayName and Description of a user type.
+func (s *UserTypeService) PartialUpdate(ctx context.Context, typeID string, t *UserType) (*UserType, *Response, error) {
+	return s.update(ctx, "POST", typeID, t)
+}
+
+func (s *UserTypeService) update(ctx context.Context, method, typeID string, t *UserType) (*UserType, *Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/types/user/%v", typeID)
+
+	var updated UserType
+	resp, err := s.client.call(ctx, method, u, newUserTypeBody(t), &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updated, resp, nil
+}
+
+// Delete deletes a user type. The default user type can't be deleted.
+func (s *UserTypeService) Delete(ctx context.Context, typeID string) (*Response, error) {
+	u := fmt.Sprintf("/api/v1/meta/types/user/%v", typeID)
+
+	return s.client.call(ctx, "DELETE", u, nil, nil)
+}
@@ -0,0 +1,20 @@
+package okta
+
+import "context"
+
+// headerFlowAPIKey carries the API key of a Workflows flow invoked by API.
+const headerFlowAPIKey = "x-api-client-token"
+
+// TriggerFlow invokes an Okta Workflows flow, POSTing payload as JSON to its
+// invoke URL with its API key. The API token of the client isn't sent, as the
+// invoke URL isn't on the host of the organisation.
+func (c *Client) TriggerFlow(ctx context.Context, invokeURL string, payload interface{}, apiKey string) (*Response, error) {
+	req, err := c.NewRequest("POST", invokeURL, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = WithRequestOptions(ctx, WithHeader(headerFlowAPIKey, apiKey))
+
+	return c.Do(ctx, req, nil)
+}